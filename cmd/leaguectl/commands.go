@@ -0,0 +1,420 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/archive"
+	"github.com/tarikbacak/insider-league-simulator/internal/db"
+	"github.com/tarikbacak/insider-league-simulator/internal/export"
+	"github.com/tarikbacak/insider-league-simulator/internal/importer"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/backtest"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/base"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/montecarlo"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/season"
+)
+
+// defaultIterations is the number of Monte Carlo iterations of a prediction
+const defaultIterations = 2000
+
+// newFlagSet creates the flag set of a subcommand
+// Parsing stops at the first non-flag argument, which starts the next command.
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	return flags
+}
+
+// parseFlags parses the flags of a subcommand and returns the remaining arguments
+func parseFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	if err := flags.Parse(args); err != nil {
+		return nil, errUsage
+	}
+	return flags.Args(), nil
+}
+
+// runInit clears the league and creates new teams and a new fixture
+func runInit(ctx context.Context, l *league, args []string) ([]string, error) {
+	rest, err := parseFlags(newFlagSet("init"), args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.ResetLeague(ctx, l.store, db.SeedLeague); err != nil {
+		return nil, err
+	}
+
+	matches, err := l.store.Matches().CountMatches(ctx, db.MatchFilter{})
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("League initialized with %d matches\n\n", matches)
+	return rest, printStandings(ctx, os.Stdout, l.store)
+}
+
+// runImport replaces the league with the fixtures and results of a
+// football-data CSV file and prints the rows that could not be imported
+func runImport(ctx context.Context, l *league, args []string) ([]string, error) {
+	flags := newFlagSet("import")
+	path := flags.String("file", "", "football-data CSV file to import (required)")
+	rest, err := parseFlags(flags, args)
+	if err != nil {
+		return nil, err
+	}
+	if *path == "" {
+		return nil, fmt.Errorf("the file to import must be given with -file")
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	season, err := importer.Parse(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *importer.Result
+	err = db.ResetLeague(ctx, l.store, func(ctx context.Context, store db.Store) error {
+		result, err = importer.Load(ctx, store, season)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Imported %d teams and %d matches (%d played) in %d weeks\n", result.Teams, result.Matches, result.Played, result.Weeks)
+	if len(result.SkippedRows) > 0 {
+		table := newTable(os.Stdout)
+		fmt.Fprintf(table, "%d rows could not be imported:\n", len(result.SkippedRows))
+		for _, row := range result.SkippedRows {
+			fmt.Fprintf(table, "  line %d\t%s\n", row.Line, row.Reason)
+		}
+		if err := table.Flush(); err != nil {
+			return nil, err
+		}
+	}
+	fmt.Println()
+	return rest, printStandings(ctx, os.Stdout, l.store)
+}
+
+// runPlayWeek plays the next week and prints its results and the standings
+func runPlayWeek(ctx context.Context, l *league, args []string) ([]string, error) {
+	rest, err := parseFlags(newFlagSet("play-week"), args)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := simulator.GetPoissonSimulator(l.store, base.Observers{}).PlayNextWeek(ctx)
+	if errors.Is(err, base.ErrSeasonComplete) {
+		fmt.Println("The season is complete, there is no week left to play")
+		return rest, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rest, printWeekResults(ctx, l.store, results)
+}
+
+// runPlayAll plays all remaining weeks and prints their results and the standings
+func runPlayAll(ctx context.Context, l *league, args []string) ([]string, error) {
+	rest, err := parseFlags(newFlagSet("play-all"), args)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := simulator.GetPoissonSimulator(l.store, base.Observers{}).PlayAllRemainingWeeks(ctx, nil)
+	if errors.Is(err, base.ErrSeasonComplete) {
+		fmt.Println("The season is complete, there is no week left to play")
+		return rest, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rest, printWeekResults(ctx, l.store, results)
+}
+
+// runStandings prints the league table
+func runStandings(ctx context.Context, l *league, args []string) ([]string, error) {
+	rest, err := parseFlags(newFlagSet("standings"), args)
+	if err != nil {
+		return nil, err
+	}
+	return rest, printStandings(ctx, os.Stdout, l.store)
+}
+
+// runPredict prints the championship predictions made after a week
+// Saved predictions are printed as they are. Otherwise the league is played
+// until the week and a new Monte Carlo prediction is made from its standings.
+func runPredict(ctx context.Context, l *league, args []string) ([]string, error) {
+	flags := newFlagSet("predict")
+	week := flags.Uint("week", 0, "week after which the champion is predicted (required)")
+	iterations := flags.Int("iterations", defaultIterations,
+		fmt.Sprintf("number of simulated seasons (%d-%d)", montecarlo.MinIterations, montecarlo.MaxIterations))
+	rest, err := parseFlags(flags, args)
+	if err != nil {
+		return nil, err
+	}
+
+	maxWeeks, err := seasonWeeks(ctx, l.store)
+	if err != nil {
+		return nil, err
+	}
+	if *week < 1 || *week > maxWeeks {
+		return nil, fmt.Errorf("week must be a number between 1 and %d", maxWeeks)
+	}
+	if *iterations < montecarlo.MinIterations || *iterations > montecarlo.MaxIterations {
+		return nil, fmt.Errorf("iterations must be a number between %d and %d", montecarlo.MinIterations, montecarlo.MaxIterations)
+	}
+
+	saved, err := l.store.Predictions().ListPredictions(ctx, *week)
+	if err != nil {
+		return nil, err
+	}
+	if len(saved) == 0 {
+		played, err := db.LastCompletedWeek(ctx, l.store)
+		if err != nil {
+			return nil, err
+		}
+		if played > *week {
+			return nil, fmt.Errorf("the league has already played week %d; predictions can only be made for week %d or later", played, played)
+		}
+
+		results, err := simulator.GetPoissonSimulator(l.store, base.Observers{}).PlayUntilWeek(ctx, *week)
+		if err != nil {
+			return nil, err
+		}
+		if len(results) > 0 {
+			if err := printResults(ctx, os.Stdout, l.store, results); err != nil {
+				return nil, err
+			}
+		}
+
+		predictor := simulator.GetMonteCarloPredictor(l.store, *iterations, base.Observers{})
+		if _, err := predictor.PredictChampionshipProbabilities(ctx, *week, nil); err != nil {
+			return nil, err
+		}
+		if saved, err = l.store.Predictions().ListPredictions(ctx, *week); err != nil {
+			return nil, err
+		}
+	}
+
+	return rest, printPredictions(ctx, os.Stdout, l.store, *week, saved)
+}
+
+// runAnalyze simulates the full season many times from the season-start
+// strengths and prints the title odds and match statistics
+func runAnalyze(ctx context.Context, l *league, args []string) ([]string, error) {
+	flags := newFlagSet("analyze")
+	seasons := flags.Int("seasons", season.DefaultSeasons, "number of simulated seasons")
+	seed := flags.Int64("seed", 0, "random seed; 0 picks a random seed")
+	points := flags.Bool("points", false, "also print the points distribution of each team")
+	rest, err := parseFlags(flags, args)
+	if err != nil {
+		return nil, err
+	}
+	if *seasons < 1 || *seasons > season.MaxSeasons {
+		return nil, fmt.Errorf("seasons must be a number between 1 and %d", season.MaxSeasons)
+	}
+
+	report, err := simulator.GetSeasonSimulator(l.store).SimulateSeasons(ctx, *seasons, *seed, nil)
+	if err != nil {
+		return nil, err
+	}
+	return rest, printSeasonReport(os.Stdout, report, *points)
+}
+
+// runBacktest replays the played weeks of the league, or of generated seasons,
+// and prints how well the models forecast them
+func runBacktest(ctx context.Context, l *league, args []string) ([]string, error) {
+	flags := newFlagSet("backtest")
+	samples := flags.Int("samples", backtest.DefaultSamples, "simulated matches per match outcome forecast")
+	iterations := flags.Int("iterations", backtest.DefaultIterations,
+		fmt.Sprintf("simulated seasons per championship forecast (%d-%d)", montecarlo.MinIterations, montecarlo.MaxIterations))
+	seed := flags.Int64("seed", 0, "random seed of the match outcome forecasts; 0 picks a random seed")
+	synthetic := flags.Int("synthetic", 0, "replay this many newly simulated seasons instead of the league")
+	output := flags.String("o", "", "also write every forecast to this CSV file")
+	rest, err := parseFlags(flags, args)
+	if err != nil {
+		return nil, err
+	}
+	if *samples < 1 || *synthetic < 0 {
+		return nil, fmt.Errorf("samples must be positive and synthetic must not be negative")
+	}
+	if *iterations < montecarlo.MinIterations || *iterations > montecarlo.MaxIterations {
+		return nil, fmt.Errorf("iterations must be a number between %d and %d", montecarlo.MinIterations, montecarlo.MaxIterations)
+	}
+
+	seasons := []db.Store{l.store}
+	if *synthetic > 0 {
+		seasons = make([]db.Store, 0, *synthetic)
+		for i := 0; i < *synthetic; i++ {
+			store := db.NewMemoryStore()
+			if err := db.SeedLeague(ctx, store); err != nil {
+				return nil, err
+			}
+			if _, err := simulator.GetPoissonSimulator(store, base.Observers{}).PlayAllRemainingWeeks(ctx, nil); err != nil {
+				return nil, err
+			}
+			seasons = append(seasons, store)
+		}
+	}
+
+	report, err := simulator.GetBacktester(*samples, *iterations, *seed).Run(ctx, seasons, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := printBacktestReport(os.Stdout, report); err != nil {
+		return nil, err
+	}
+	if *output == "" {
+		return rest, nil
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeForecasts(file, report.Forecasts); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "Forecasts written to %s\n", *output)
+	return rest, nil
+}
+
+// runExport writes the league as JSON or an Excel workbook, or one of its datasets as CSV
+func runExport(ctx context.Context, l *league, args []string) ([]string, error) {
+	flags := newFlagSet("export")
+	format := flags.String("format", export.FormatJSON, "output format: json, csv or xlsx")
+	dataset := flags.String("data", export.DatasetMatches, "dataset written as CSV: teams, matches, results, standings or predictions")
+	output := flags.String("o", "", "output file (default standard output)")
+	rest, err := parseFlags(flags, args)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(export.Formats, *format) {
+		return nil, fmt.Errorf("unknown format %q, expected json, csv or xlsx", *format)
+	}
+	if *format == export.FormatCSV && !slices.Contains(export.Datasets, *dataset) {
+		return nil, fmt.Errorf("unknown dataset %q, expected one of %s", *dataset, strings.Join(export.Datasets, ", "))
+	}
+
+	league, err := export.Build(ctx, l.store)
+	if err != nil {
+		return nil, err
+	}
+
+	if *output == "" {
+		return rest, export.Write(os.Stdout, league, *format, *dataset)
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		return nil, err
+	}
+	if err := export.Write(file, league, *format, *dataset); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "League exported to %s\n", *output)
+	return rest, nil
+}
+
+// runArchive writes the complete league state as a JSON archive
+func runArchive(ctx context.Context, l *league, args []string) ([]string, error) {
+	flags := newFlagSet("archive")
+	output := flags.String("o", "", "output file (default standard output)")
+	rest, err := parseFlags(flags, args)
+	if err != nil {
+		return nil, err
+	}
+
+	leagueArchive, err := archive.Build(ctx, l.store)
+	if err != nil {
+		return nil, err
+	}
+
+	if *output == "" {
+		return rest, archive.Write(os.Stdout, leagueArchive)
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		return nil, err
+	}
+	if err := archive.Write(file, leagueArchive); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "League archived to %s (week %d)\n", *output, leagueArchive.Week)
+	return rest, nil
+}
+
+// runRestore replaces the league with the content of a JSON archive
+func runRestore(ctx context.Context, l *league, args []string) ([]string, error) {
+	flags := newFlagSet("restore")
+	path := flags.String("file", "", "league archive to restore (required)")
+	rest, err := parseFlags(flags, args)
+	if err != nil {
+		return nil, err
+	}
+	if *path == "" {
+		return nil, fmt.Errorf("the archive to restore must be given with -file")
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	leagueArchive, err := archive.Read(file)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := archive.Restore(ctx, l.store, leagueArchive)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Restored %d teams, %d players and %d matches (%d played, week %d)\n",
+		summary.Teams, summary.Players, summary.Matches, summary.Played, summary.Week)
+	for _, warning := range summary.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+	fmt.Println()
+	return rest, printStandings(ctx, os.Stdout, l.store)
+}
+
+// seasonWeeks returns the number of weeks of the season: those of a double
+// round-robin, or the last week of an imported fixture if it lasts longer
+func seasonWeeks(ctx context.Context, store db.Store) (uint, error) {
+	teams, err := store.Teams().CountTeams(ctx)
+	if err != nil || teams < 2 {
+		return 0, err
+	}
+	matches, err := store.Matches().ListMatches(ctx, db.MatchFilter{})
+	if err != nil {
+		return 0, err
+	}
+	weeks := uint(teams-1) * 2
+	if len(matches) > 0 {
+		weeks = max(weeks, matches[len(matches)-1].Week)
+	}
+	return weeks, nil
+}
@@ -0,0 +1,162 @@
+// Package main is a command-line interface that plays the league without the HTTP server
+//
+// Usage:
+//
+//	leaguectl [-memory] <command> [flags] [<command> [flags] ...]
+//
+// Commands:
+//
+//	init                          clear the league and create a new fixture
+//	import -file results.csv      replace the league with a football-data CSV file
+//	play-week                     play the next week
+//	play-all                      play all remaining weeks
+//	standings                     print the league table
+//	predict -week N               play until week N and predict the champion
+//	analyze [-seasons N] [-seed]  simulate the full season many times
+//	backtest [-synthetic N] [-o]  score the model forecasts of the played weeks
+//	export [-format] [-data] [-o] write the league as JSON, CSV or XLSX
+//	archive [-o file]             write the complete league state as a JSON archive
+//	restore -file archive.json    replace the league with a JSON archive
+//
+// The league is read from and saved to the database configured like the server.
+// With -memory a freshly seeded league is kept in memory instead and discarded
+// on exit, so the commands of a session are chained in one invocation:
+//
+//	leaguectl -memory play-week play-week predict -week 4 standings
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/tarikbacak/insider-league-simulator/config"
+	"github.com/tarikbacak/insider-league-simulator/internal/db"
+)
+
+// errUsage reports invalid arguments; the usage has already been printed
+var errUsage = errors.New("invalid arguments")
+
+// league is the league the commands operate on
+type league struct {
+	store db.Store
+}
+
+// command runs one subcommand with its arguments and returns the arguments
+// that follow it
+type command func(ctx context.Context, l *league, args []string) ([]string, error)
+
+// commands maps the subcommand names to their implementation
+var commands = map[string]command{
+	"init":      runInit,
+	"import":    runImport,
+	"play-week": runPlayWeek,
+	"play-all":  runPlayAll,
+	"standings": runStandings,
+	"predict":   runPredict,
+	"analyze":   runAnalyze,
+	"backtest":  runBacktest,
+	"export":    runExport,
+	"archive":   runArchive,
+	"restore":   runRestore,
+}
+
+func main() {
+	memory := flag.Bool("memory", false, "play a new in-memory league instead of the database")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	// The first argument must be a command; the others are checked as they run
+	if _, ok := commands[flag.Arg(0)]; !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+
+	// Stop simulations and predictions on Ctrl+C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	l, err := openLeague(ctx, *memory)
+	if err != nil {
+		log.Fatalf("Could not open league: %v", err)
+	}
+
+	args := flag.Args()
+	for len(args) > 0 {
+		name := args[0]
+		run, ok := commands[name]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown command %q\n", name)
+			usage()
+			os.Exit(2)
+		}
+		if args, err = run(ctx, l, args[1:]); err != nil {
+			if errors.Is(err, errUsage) {
+				os.Exit(2)
+			}
+			log.Fatalf("%s failed: %v", name, err)
+		}
+	}
+}
+
+// openLeague opens the configured database, or seeds a new in-memory league
+func openLeague(ctx context.Context, memory bool) (*league, error) {
+	if memory {
+		store := db.NewMemoryStore()
+		if err := db.SeedLeague(ctx, store); err != nil {
+			return nil, err
+		}
+		return &league{store: store}, nil
+	}
+
+	// Load configuration (.env file and environment variables)
+	cfg := config.Load()
+	database, err := db.Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &league{store: db.NewSQLStore(database)}, nil
+}
+
+// usage prints the available commands
+func usage() {
+	fmt.Fprint(os.Stderr, `usage: leaguectl [-memory] <command> [flags] [<command> [flags] ...]
+
+commands:
+  init                      clear the league and create a new fixture
+  import -file results.csv  replace the league with the teams, fixtures and
+                            results of a football-data.co.uk CSV file
+  play-week                 play the next week
+  play-all                  play all remaining weeks
+  standings                 print the league table
+  predict -week N           play until week N and predict the champion
+  analyze [-seasons N] [-seed S] [-points]
+                            simulate the full season N times and print title
+                            odds, points and goals per match
+  backtest [-synthetic N] [-samples N] [-iterations N] [-seed S] [-o file]
+                            replay the played weeks (or N simulated seasons)
+                            and print the Brier score, log-loss, RPS and
+                            calibration of each model; -o writes every forecast
+                            as CSV
+  export [-format json|csv|xlsx] [-data teams|matches|results|standings|predictions] [-o file]
+                            write the league as JSON or an Excel workbook, or
+                            one dataset as CSV
+  archive [-o file]         write the complete league state (teams, squads,
+                            statistics, results, timelines, predictions and
+                            model parameters) as a JSON archive
+  restore -file archive.json
+                            replace the league with a JSON archive
+
+flags:
+`)
+	flag.PrintDefaults()
+}
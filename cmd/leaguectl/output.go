@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/db"
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/backtest"
+	simModels "github.com/tarikbacak/insider-league-simulator/internal/simulator/models"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/season"
+)
+
+// newTable creates a writer that aligns tab separated columns
+func newTable(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+}
+
+// printWeekResults prints simulated results followed by the standings
+func printWeekResults(ctx context.Context, store db.Store, results []simModels.MatchResult) error {
+	if err := printResults(ctx, os.Stdout, store, results); err != nil {
+		return err
+	}
+	return printStandings(ctx, os.Stdout, store)
+}
+
+// printResults prints match results grouped by week
+func printResults(ctx context.Context, w io.Writer, store db.Store, results []simModels.MatchResult) error {
+	names, err := teamNames(ctx, store)
+	if err != nil {
+		return err
+	}
+
+	table := newTable(w)
+	var week uint
+	for _, result := range results {
+		if result.Week != week {
+			if week != 0 {
+				fmt.Fprintln(table)
+			}
+			week = result.Week
+			fmt.Fprintf(table, "Week %d\n", week)
+		}
+		fmt.Fprintf(table, "  %s\t%d - %d\t%s\n", names[result.HomeTeamID], result.HomeGoals, result.AwayGoals, names[result.AwayTeamID])
+	}
+	fmt.Fprintln(table)
+	return table.Flush()
+}
+
+// printStandings prints the league table
+func printStandings(ctx context.Context, w io.Writer, store db.Store) error {
+	standings, err := db.CalculateStandings(ctx, store)
+	if err != nil {
+		return err
+	}
+
+	table := newTable(w)
+	fmt.Fprintln(table, "#\tTeam\tP\tW\tD\tL\tGF\tGA\tGD\tPts")
+	for i, standing := range standings {
+		fmt.Fprintf(table, "%d\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%+d\t%d\n", i+1, standing.TeamName,
+			standing.Played, standing.Won, standing.Drawn, standing.Lost,
+			standing.GoalsFor, standing.GoalsAgainst, standing.GoalDifference, standing.Points)
+	}
+	fmt.Fprintln(table)
+	return table.Flush()
+}
+
+// printPredictions prints the championship predictions of a week, most likely champion first
+func printPredictions(ctx context.Context, w io.Writer, store db.Store, week uint, predictions []models.Prediction) error {
+	names, err := teamNames(ctx, store)
+	if err != nil {
+		return err
+	}
+
+	table := newTable(w)
+	fmt.Fprintf(table, "Championship predictions after week %d\n", week)
+	fmt.Fprintln(table, "Team\tProbability")
+	for _, prediction := range predictions {
+		fmt.Fprintf(table, "%s\t%6.2f%%\n", names[prediction.TeamID], prediction.Probability)
+	}
+	fmt.Fprintln(table)
+	return table.Flush()
+}
+
+// printSeasonReport prints the title odds, points and match statistics of a
+// season analysis, and optionally the share of seasons ending on each points total
+func printSeasonReport(w io.Writer, report *season.Report, points bool) error {
+	table := newTable(w)
+	fmt.Fprintf(table, "%d simulated seasons (seed %d, %d ms)\n", report.Seasons, report.Seed, report.DurationMS)
+	fmt.Fprintf(table, "Goals per match: %.2f (home %.2f, away %.2f)\n", report.AverageGoals, report.AverageHome, report.AverageAway)
+	fmt.Fprintf(table, "Home wins %.1f%%, draws %.1f%%, away wins %.1f%%\n\n", report.HomeWinRate, report.DrawRate, report.AwayWinRate)
+
+	fmt.Fprint(table, "Team\tTitle")
+	for position := 2; position <= len(report.Teams); position++ {
+		fmt.Fprintf(table, "\t#%d", position)
+	}
+	fmt.Fprintln(table, "\tAvg Pts\tMin\tMax\tGF\tGA")
+	for _, team := range report.Teams {
+		fmt.Fprintf(table, "%s\t%.1f%%", team.TeamName, team.TitleOdds)
+		for _, odds := range team.PositionOdds[1:] {
+			fmt.Fprintf(table, "\t%.1f%%", odds)
+		}
+		fmt.Fprintf(table, "\t%.2f\t%d\t%d\t%.2f\t%.2f\n", team.AveragePoints, team.MinPoints, team.MaxPoints, team.AverageGoalsFor, team.AverageGoalsAgainst)
+	}
+	fmt.Fprintln(table)
+	if !points {
+		return table.Flush()
+	}
+
+	// One column per points total reached by any team
+	lowest, highest := report.Teams[0].MinPoints, report.Teams[0].MaxPoints
+	for _, team := range report.Teams {
+		lowest, highest = min(lowest, team.MinPoints), max(highest, team.MaxPoints)
+	}
+	fmt.Fprint(table, "Points")
+	for p := lowest; p <= highest; p++ {
+		fmt.Fprintf(table, "\t%d", p)
+	}
+	fmt.Fprintln(table)
+	for _, team := range report.Teams {
+		shares := make(map[int]float64, len(team.PointsDistribution))
+		for _, frequency := range team.PointsDistribution {
+			shares[frequency.Points] = frequency.Percentage
+		}
+		fmt.Fprint(table, team.TeamName)
+		for p := lowest; p <= highest; p++ {
+			fmt.Fprintf(table, "\t%.1f", shares[p])
+		}
+		fmt.Fprintln(table)
+	}
+	fmt.Fprintln(table)
+	return table.Flush()
+}
+
+// printBacktestReport prints the scores and calibration curves of every model
+func printBacktestReport(w io.Writer, report *backtest.Report) error {
+	table := newTable(w)
+	fmt.Fprintf(table, "Backtest of %d seasons, %d weeks, %d matches (seed %d, %d ms)\n\n", report.Seasons, report.Weeks, report.Matches, report.Seed, report.DurationMS)
+
+	fmt.Fprintln(table, "Model\tTarget\tForecasts\tBrier\tLog-loss\tRPS")
+	for _, model := range report.Models {
+		rps := "-"
+		if model.RPS != nil {
+			rps = fmt.Sprintf("%.4f", *model.RPS)
+		}
+		fmt.Fprintf(table, "%s\t%s\t%d\t%.4f\t%.4f\t%s\n", model.Model, model.Target, model.Forecasts, model.Brier, model.LogLoss, rps)
+	}
+	fmt.Fprintln(table)
+
+	fmt.Fprintln(table, "Calibration\tProbability\tCount\tPredicted\tObserved")
+	for _, model := range report.Models {
+		for _, bin := range model.Calibration {
+			if bin.Count == 0 {
+				continue
+			}
+			fmt.Fprintf(table, "%s\t%.1f-%.1f\t%d\t%.3f\t%.3f\n", model.Model, bin.Lower, bin.Upper, bin.Count, bin.MeanPredicted, bin.ObservedRate)
+		}
+	}
+	fmt.Fprintln(table)
+	return table.Flush()
+}
+
+// writeForecasts writes the forecasts of a backtest as CSV, one row per outcome
+func writeForecasts(w io.Writer, forecasts []backtest.Forecast) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"season", "week", "model", "target", "match_id", "outcome", "probability", "observed"})
+	for _, forecast := range forecasts {
+		for i, outcome := range forecast.Outcomes {
+			observed := "0"
+			if i == forecast.Observed {
+				observed = "1"
+			}
+			writer.Write([]string{
+				strconv.Itoa(forecast.Season), strconv.FormatUint(uint64(forecast.Week), 10),
+				forecast.Model, forecast.Target, strconv.FormatUint(uint64(forecast.MatchID), 10), outcome,
+				strconv.FormatFloat(forecast.Probabilities[i], 'f', 4, 64), observed,
+			})
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// teamNames maps the team IDs to their names
+func teamNames(ctx context.Context, store db.Store) (map[uint]string, error) {
+	teams, err := store.Teams().ListTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uint]string, len(teams))
+	for _, team := range teams {
+		names[team.ID] = team.Name
+	}
+	return names, nil
+}
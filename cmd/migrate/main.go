@@ -0,0 +1,73 @@
+// Package main applies and reverts the versioned database schema migrations
+//
+// Usage:
+//
+//	migrate up          apply all pending migrations
+//	migrate down [n]    revert the last n applied migrations (default 1)
+//	migrate status      list the migrations and when they were applied
+//
+// The database is selected with the same environment variables as the server.
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/tarikbacak/insider-league-simulator/config"
+	"github.com/tarikbacak/insider-league-simulator/internal/db"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	// Load configuration (.env file and environment variables)
+	cfg := config.Load()
+
+	database, err := db.Connect(cfg)
+	if err != nil {
+		log.Fatalf("Could not open database: %v", err)
+	}
+
+	switch os.Args[1] {
+	case "up":
+		if err := db.Migrate(database); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		log.Println("Database is up to date")
+	case "down":
+		steps := 1
+		if len(os.Args) > 2 {
+			steps, err = strconv.Atoi(os.Args[2])
+			if err != nil || steps < 1 {
+				log.Fatalf("Invalid number of steps %q", os.Args[2])
+			}
+		}
+		if err := db.MigrateDown(database, steps); err != nil {
+			log.Fatalf("Rollback failed: %v", err)
+		}
+	case "status":
+		statuses, err := db.GetMigrationStatus(database)
+		if err != nil {
+			log.Fatalf("Could not read migration status: %v", err)
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%03d_%-28s %s\n", status.Version, status.Name, applied)
+		}
+	default:
+		usage()
+	}
+}
+
+// usage prints the available commands and exits
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: migrate up | down [n] | status")
+	os.Exit(2)
+}
@@ -7,6 +7,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 
@@ -15,22 +16,44 @@ import (
 	_ "github.com/tarikbacak/insider-league-simulator/docs" // Swagger docs
 	"github.com/tarikbacak/insider-league-simulator/internal/api"
 	"github.com/tarikbacak/insider-league-simulator/internal/db"
+	"github.com/tarikbacak/insider-league-simulator/internal/jobs"
+	"github.com/tarikbacak/insider-league-simulator/internal/stream"
+	"github.com/tarikbacak/insider-league-simulator/internal/webhook"
 )
 
 func main() {
-	// Initialize configuration (load .env file)
-	config.Init()
-	// Initialize database connection
-	db.InitDB()
+	// Load configuration (.env file and environment variables)
+	cfg := config.Load()
+
+	// Connect to the database, retrying while it is unavailable, and migrate the schema
+	database, err := db.Open(cfg)
+	if err != nil {
+		log.Fatalf("Could not open database: %v", err)
+	}
 
 	// Initialize database data (create fixtures)
-	db.InitializeData()
+	if err := db.InitializeData(database); err != nil {
+		log.Fatalf("Could not initialize data: %v", err)
+	}
+
+	// Build the API server from its dependencies
+	server := api.NewServer(api.Dependencies{
+		DB:       database,
+		Config:   cfg,
+		Hub:      stream.NewHub(),
+		Jobs:     jobs.NewQueue(database, jobs.DefaultWorkers),
+		Webhooks: webhook.NewDispatcher(database),
+	})
+
+	// Start the background job workers and the webhook deliveries
+	if err := server.Start(context.Background()); err != nil {
+		log.Fatalf("Could not start background workers: %v", err)
+	}
 
 	// Set up the router
-	router := api.SetupRouter()
+	router := server.Router()
 
 	// Get server port from config
-	cfg := config.GetConfig()
 	port := ":" + cfg.Server.Port
 
 	// Start the server
@@ -1,9 +1,11 @@
 package config
 
 import (
-	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -14,25 +16,55 @@ type Config struct {
 	Server   ServerConfig   `json:"server"`
 }
 
+// Supported database drivers
+const (
+	DriverPostgres = "postgres" // PostgreSQL server
+	DriverSQLite   = "sqlite"   // SQLite file or in-memory database
+)
+
+// SQLiteMemory is the SQLite path that keeps the database in memory
+const SQLiteMemory = ":memory:"
+
 // DatabaseConfig holds the database connection details
 type DatabaseConfig struct {
+	Driver   string `json:"driver"` // DriverPostgres or DriverSQLite
+	Path     string `json:"path"`   // SQLite database file, or SQLiteMemory
 	Host     string `json:"host"`
 	Port     string `json:"port"`
 	User     string `json:"user"`
 	Password string `json:"password"`
 	DBName   string `json:"db_name"`
+
+	// PostgreSQL connection options
+	URL              string        `json:"-"`                 // Complete DSN (URL or key=value); overrides the fields above
+	SSLMode          string        `json:"ssl_mode"`          // disable, allow, prefer, require, verify-ca or verify-full
+	SSLRootCert      string        `json:"ssl_root_cert"`     // CA certificate file used by verify-ca and verify-full
+	ConnectTimeout   time.Duration `json:"connect_timeout"`   // Maximum wait for a connection; 0 waits indefinitely
+	StatementTimeout time.Duration `json:"statement_timeout"` // Maximum duration of a statement; 0 disables the limit
+	SearchPath       string        `json:"search_path"`       // Schemas searched for unqualified table names
+
+	// Connection pool
+	MaxOpenConns    int           `json:"max_open_conns"`     // Maximum open connections; 0 means unlimited
+	MaxIdleConns    int           `json:"max_idle_conns"`     // Maximum idle connections kept in the pool
+	ConnMaxLifetime time.Duration `json:"conn_max_lifetime"`  // Connections are replaced after this age; 0 keeps them
+	ConnMaxIdleTime time.Duration `json:"conn_max_idle_time"` // Idle connections are closed after this time; 0 keeps them
+
+	// Startup
+	ConnectRetries int           `json:"connect_retries"` // Further connection attempts after the first one fails
+	ConnectBackoff time.Duration `json:"connect_backoff"` // Wait before the first retry, doubled after each attempt
+	AutoMigrate    bool          `json:"auto_migrate"`    // Apply pending schema migrations when the database is opened
 }
 
 // ServerConfig holds the server configuration details
 type ServerConfig struct {
-	Port string `json:"port"`
+	Port           string        `json:"port"`
+	RequestTimeout time.Duration `json:"request_timeout"` // Maximum duration of an API request; 0 disables the limit
 }
 
-// Global config variable for Singleton pattern
-var AppConfig *Config
-
-// Init loads the configuration file and initializes the global variable
-func Init() {
+// Load reads the configuration from the .env file and environment variables
+// Missing values fall back to their defaults. The returned configuration is
+// passed explicitly to the packages that need it.
+func Load() *Config {
 	// Load .env file - if it fails, default values will be used
 	err := godotenv.Load()
 	if err != nil {
@@ -40,20 +72,40 @@ func Init() {
 	}
 
 	// Create the configuration object
-	AppConfig = &Config{
+	cfg := &Config{
 		Database: DatabaseConfig{
+			Driver:   getEnv("DB_DRIVER", DriverPostgres),
+			Path:     getEnv("DB_PATH", "insider_league.db"),
 			Host:     getEnv("DB_HOST", "localhost"),
 			Port:     getEnv("DB_PORT", "5432"),
 			User:     getEnv("DB_USER", "postgres"),
 			Password: getEnv("DB_PASSWORD", "password"),
 			DBName:   getEnv("DB_NAME", "insider_league"),
+
+			URL:              os.Getenv("DATABASE_URL"),
+			SSLMode:          getEnv("DB_SSLMODE", "require"),
+			SSLRootCert:      os.Getenv("DB_SSLROOTCERT"),
+			ConnectTimeout:   getDurationEnv("DB_CONNECT_TIMEOUT", 10*time.Second),
+			StatementTimeout: getDurationEnv("DB_STATEMENT_TIMEOUT", 0),
+			SearchPath:       os.Getenv("DB_SEARCH_PATH"),
+
+			MaxOpenConns:    getIntEnv("DB_MAX_OPEN_CONNS", 25),
+			MaxIdleConns:    getIntEnv("DB_MAX_IDLE_CONNS", 5),
+			ConnMaxLifetime: getDurationEnv("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+			ConnMaxIdleTime: getDurationEnv("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+
+			ConnectRetries: getIntEnv("DB_CONNECT_RETRIES", 5),
+			ConnectBackoff: getDurationEnv("DB_CONNECT_BACKOFF", time.Second),
+			AutoMigrate:    getBoolEnv("DB_AUTO_MIGRATE", true),
 		},
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "8080"),
+			Port:           getEnv("SERVER_PORT", "8080"),
+			RequestTimeout: getDurationEnv("REQUEST_TIMEOUT", 30*time.Second),
 		},
 	}
 
 	log.Println("Configuration successfully loaded")
+	return cfg
 }
 
 // getEnv reads an environment variable, returns the default value if not found
@@ -64,21 +116,95 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getDurationEnv reads a duration (e.g. "30s") from an environment variable,
+// returns the default value if it is not set or invalid
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		log.Printf("Warning: invalid %s %q, using %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
+// getIntEnv reads a non-negative integer from an environment variable,
+// returns the default value if it is not set or invalid
+func getIntEnv(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		log.Printf("Warning: invalid %s %q, using %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+// getBoolEnv reads a boolean (e.g. "true", "0") from an environment variable,
+// returns the default value if it is not set or invalid
+func getBoolEnv(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Warning: invalid %s %q, using %t", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
 // GetDatabaseURL constructs the PostgreSQL connection string
+// It is only used with DriverPostgres; SQLite connects to Database.Path.
+// Database.URL is returned unchanged when set, otherwise a key=value DSN is
+// built from the individual settings.
 func (c *Config) GetDatabaseURL() string {
-	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=require",
-		c.Database.Host,
-		c.Database.Port,
-		c.Database.User,
-		c.Database.Password,
-		c.Database.DBName,
-	)
+	if c.Database.URL != "" {
+		return c.Database.URL
+	}
+
+	params := [][2]string{
+		{"host", c.Database.Host},
+		{"port", c.Database.Port},
+		{"user", c.Database.User},
+		{"password", c.Database.Password},
+		{"dbname", c.Database.DBName},
+		{"sslmode", c.Database.SSLMode},
+		{"sslrootcert", c.Database.SSLRootCert},
+		{"search_path", c.Database.SearchPath},
+	}
+	if c.Database.ConnectTimeout > 0 {
+		// connect_timeout is in whole seconds; round up so a short timeout is not disabled
+		seconds := int64((c.Database.ConnectTimeout + time.Second - 1) / time.Second)
+		params = append(params, [2]string{"connect_timeout", strconv.FormatInt(seconds, 10)})
+	}
+	if c.Database.StatementTimeout > 0 {
+		params = append(params, [2]string{"statement_timeout", strconv.FormatInt(c.Database.StatementTimeout.Milliseconds(), 10)})
+	}
+
+	var parts []string
+	for _, param := range params {
+		if param[1] != "" {
+			parts = append(parts, param[0]+"="+quoteDSNValue(param[1]))
+		}
+	}
+	return strings.Join(parts, " ")
 }
 
-// GetConfig returns the global configuration object
-func GetConfig() *Config {
-	if AppConfig == nil {
-		log.Fatal("Configuration not initialized. Call config.Init() function.")
+// quoteDSNValue quotes a key=value DSN value that is empty or contains
+// spaces, quotes or backslashes
+func quoteDSNValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, " '\\\t") {
+		return value
 	}
-	return AppConfig
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
 }
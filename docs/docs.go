@@ -35,6 +35,195 @@ const docTemplate = `{
                 }
             }
         },
+        "/analysis/seasons": {
+            "post": {
+                "description": "Plays the whole fixture from the season-start team strengths thousands of times with the Poisson match model and reports title odds, finishing positions, points distributions, goals per match and home/draw/away rates. Nothing is saved; the same seed reproduces the same report.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analysis"
+                ],
+                "summary": "Simulate seasons",
+                "parameters": [
+                    {
+                        "description": "Number of seasons and random seed",
+                        "name": "analysis",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.SeasonAnalysisRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/season.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/archive": {
+            "get": {
+                "description": "Downloads a self-describing JSON archive of the complete league: teams with their ratings, statistics and squads, the fixture with its results, match timelines, injuries/suspensions, the predictions of every week, the match model parameters and the random seed of the league. Records keep their IDs. POST the archive to /archive/restore, in this or another instance, to restore the league exactly; with the same match model parameters, matches played afterwards have the same results in both instances. Championship predictions are Monte Carlo estimates and vary between runs.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "archive"
+                ],
+                "summary": "Download league archive",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/archive.Archive"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/archive/restore": {
+            "post": {
+                "description": "Replaces the league with a JSON archive downloaded from /archive, sent as the \"file\" field of a multipart form or as the request body. Teams, statistics, squads, results, timelines, injuries/suspensions and predictions are restored with their IDs, and the random seed so that matches played from now on have the same results as in the archiving instance. Warnings lists differences, such as other match model parameters or a missing seed, that make matches played from now on behave differently. Like /init, the current league and its snapshots are deleted.",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "archive"
+                ],
+                "summary": "Restore league archive",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "League archive",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.ArchiveRestoreResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/export": {
+            "get": {
+                "description": "Downloads the teams, fixtures, results, standings and predictions history. json returns the complete league as one document and xlsx an Excel workbook with one worksheet per dataset; csv returns the dataset selected by the dataset parameter.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export the league",
+                "parameters": [
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Export format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "teams",
+                            "matches",
+                            "results",
+                            "standings",
+                            "predictions"
+                        ],
+                        "type": "string",
+                        "default": "matches",
+                        "description": "Dataset of a CSV export",
+                        "name": "dataset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/export.League"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/health": {
             "get": {
                 "description": "Checks the health of the service",
@@ -55,6 +244,56 @@ const docTemplate = `{
                 }
             }
         },
+        "/import": {
+            "post": {
+                "description": "Replaces the league with the teams, fixtures and results of a CSV file in the football-data.co.uk layout (Date, HomeTeam, AwayTeam, FTHG, FTAG; or Date, Home, Away, HG, AG). The file is sent as the \"file\" field of a multipart form or as the request body. Matches without a score are imported as unplayed fixtures, weeks are numbered in date order and team strengths are fitted from the results. Rows that could not be mapped are listed in skipped_rows. Like /init, the current league and its snapshots are deleted.",
+                "consumes": [
+                    "multipart/form-data",
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "import"
+                ],
+                "summary": "Import historical results",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "football-data CSV file",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/api.ImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/init": {
             "post": {
                 "description": "Resets and initializes the database with new random fixtures",
@@ -81,21 +320,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/matches": {
+        "/jobs": {
             "get": {
-                "description": "Returns list of matches for a specific week or all weeks",
+                "description": "Returns background jobs, newest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "matches"
+                    "jobs"
                 ],
-                "summary": "Get match list",
+                "summary": "List background jobs",
                 "parameters": [
+                    {
+                        "enum": [
+                            "queued",
+                            "running",
+                            "succeeded",
+                            "failed",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "description": "Job status",
+                        "name": "status",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Week number",
-                        "name": "week",
+                        "description": "Maximum number of jobs to return (1-100, default 20)",
+                        "name": "limit",
                         "in": "query"
                     }
                 ],
@@ -103,7 +355,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.MatchesResponse"
+                            "$ref": "#/definitions/api.JobsResponse"
                         }
                     },
                     "400": {
@@ -119,23 +371,35 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/matches/all": {
+            },
             "post": {
-                "description": "Simulates all remaining unplayed matches until the end of the season",
+                "description": "Queues a long-running operation and returns immediately with the job ID. \"predictions\" recalculates the championship predictions of a week (params: {\"week\": n}, 0 or omitted for the last completed week); \"play_all\" simulates every remaining week (params: {\"predictions\": true} to refresh predictions afterwards). Poll GET /jobs/{id} for progress and the result.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "simulation"
+                    "jobs"
+                ],
+                "summary": "Submit background job",
+                "parameters": [
+                    {
+                        "description": "Job type and parameters",
+                        "name": "job",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.JobRequest"
+                        }
+                    }
                 ],
-                "summary": "Simulate all remaining weeks",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/api.SimulationResponse"
+                            "$ref": "#/definitions/api.JobResponse"
                         }
                     },
                     "400": {
@@ -149,25 +413,40 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/matches/next": {
-            "post": {
-                "description": "Simulates all matches for the next unplayed week using Poisson distribution",
+        "/jobs/{id}": {
+            "get": {
+                "description": "Returns the status and progress (0-100) of a job; once it has succeeded the result holds the same response the synchronous endpoint returns",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "simulation"
+                    "jobs"
+                ],
+                "summary": "Get background job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Simulate next week's matches",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.SimulationResponse"
+                            "$ref": "#/definitions/api.JobResponse"
                         }
                     },
                     "400": {
@@ -176,6 +455,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -185,22 +470,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/predictions": {
-            "get": {
-                "description": "Returns championship predictions based on Monte Carlo simulation for a specific week.",
+        "/jobs/{id}/cancel": {
+            "post": {
+                "description": "Cancels a queued job immediately. A running job is stopped at its next checkpoint (between Monte Carlo iteration batches or simulated weeks) and then reported as cancelled; weeks already simulated by a play_all job are kept and listed in its result.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "predictions"
+                    "jobs"
                 ],
-                "summary": "Get championship predictions",
+                "summary": "Cancel background job",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Week number for prediction",
-                        "name": "week",
-                        "in": "query",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -208,7 +493,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.PredictionsListResponse"
+                            "$ref": "#/definitions/api.JobResponse"
                         }
                     },
                     "400": {
@@ -217,6 +502,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -226,21 +523,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/standings": {
+        "/matches": {
             "get": {
-                "description": "Returns current league table with teams' points, goals, and other statistics",
+                "description": "Returns list of matches for a specific week or all weeks",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "standings"
+                    "matches"
+                ],
+                "summary": "Get match list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Week number",
+                        "name": "week",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get league standings",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.StandingsResponse"
+                            "$ref": "#/definitions/api.MatchesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
                     "500": {
@@ -251,236 +562,2572 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "api.APIInfoResponse": {
-            "type": "object",
-            "properties": {
+        },
+        "/matches/all": {
+            "post": {
+                "description": "Simulates all remaining unplayed matches until the end of the season and returns the weeks played, each result with lambdas and the final standings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "simulation"
+                ],
+                "summary": "Simulate all remaining weeks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key that makes retries of this request replay the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also refresh championship predictions (omitted once the season is complete)",
+                        "name": "predictions",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.WeekSimulationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/matches/next": {
+            "post": {
+                "description": "Simulates all matches for the next unplayed week using Poisson distribution and returns the results with lambdas, the updated standings and, with predictions=true, refreshed championship predictions. The week is persisted in a single transaction; if any match fails the whole week is rolled back and the failing match IDs are returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "simulation"
+                ],
+                "summary": "Simulate next week's matches",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key that makes retries of this request replay the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also refresh championship predictions after the week",
+                        "name": "predictions",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.WeekSimulationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/matches/play-until": {
+            "post": {
+                "description": "Simulates all unplayed matches up to and including the given week in a single transaction and returns the results with the resulting standings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "simulation"
+                ],
+                "summary": "Play league up to a week",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key that makes retries of this request replay the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Target week",
+                        "name": "week",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.PlayUntilResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/matches/rewind": {
+            "post": {
+                "description": "Clears the results of every week after the given one, together with their match events, injuries/suspensions and predictions. A snapshot is taken first so the rewind can be undone. Week 0 rewinds to the start of the season.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "history"
+                ],
+                "summary": "Rewind league to a week",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "Last week to keep",
+                        "name": "week",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RewindResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/matches/{id}/events": {
+            "get": {
+                "description": "Returns the simulated timeline of a match (goals, cards, substitutions, half-time and full-time)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "matches"
+                ],
+                "summary": "Get match events",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Match ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.MatchEventsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/matches/{id}/simulate": {
+            "post": {
+                "description": "Simulates one fixture by ID using Poisson distribution and returns the score with the lambdas used. Already played matches are only re-simulated when force=true.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "simulation"
+                ],
+                "summary": "Simulate a single match",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key that makes retries of this request replay the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Match ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Re-simulate the match even if it has already been played",
+                        "name": "force",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.MatchSimulationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/players/{id}": {
+            "get": {
+                "description": "Returns the details of a single player",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "players"
+                ],
+                "summary": "Get player",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.PlayerResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates the details of an existing player",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "players"
+                ],
+                "summary": "Update player",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Player details",
+                        "name": "player",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.PlayerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.PlayerResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes a player from their team's squad (goals already scored are kept in statistics)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "players"
+                ],
+                "summary": "Delete player",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/predictions": {
+            "get": {
+                "description": "Returns championship predictions based on Monte Carlo simulation for a specific week.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "predictions"
+                ],
+                "summary": "Get championship predictions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Week number for prediction",
+                        "name": "week",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.PredictionsListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/snapshots": {
+            "get": {
+                "description": "Returns the history of saved league snapshots, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "history"
+                ],
+                "summary": "List league snapshots",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SnapshotsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Saves the current results, match events, injuries/suspensions and predictions so the league can be restored to this point later",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "history"
+                ],
+                "summary": "Create league snapshot",
+                "parameters": [
+                    {
+                        "description": "Snapshot label",
+                        "name": "snapshot",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.SnapshotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.LeagueSnapshot"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/snapshots/{id}/restore": {
+            "post": {
+                "description": "Replaces the current league progress with the state saved in a snapshot. The current state is saved as a new snapshot first so the restore can be undone.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "history"
+                ],
+                "summary": "Restore league snapshot",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Snapshot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RestoreResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/standings": {
+            "get": {
+                "description": "Returns current league table with teams' points, goals, and other statistics",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "standings"
+                ],
+                "summary": "Get league standings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.StandingsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/scorers": {
+            "get": {
+                "description": "Returns players ordered by goals scored (then assists) in simulated matches",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "players"
+                ],
+                "summary": "Get top scorers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of players to return (1-100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.TopScorersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stream": {
+            "get": {
+                "description": "Opens a Server-Sent Events stream. A \"connected\" event with the current standings is sent first, followed by \"match_result\", \"week_completed\", \"standings\", \"predictions\" and \"league_reset\" events as the league changes.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "stream"
+                ],
+                "summary": "Stream league updates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/stream.Event"
+                        }
+                    }
+                }
+            }
+        },
+        "/teams/{id}/players": {
+            "get": {
+                "description": "Returns all players in a team's squad with their injury/suspension status and its effect on team strength",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "players"
+                ],
+                "summary": "Get team squad",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Team ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SquadResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Adds a new player to the squad of the specified team",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "players"
+                ],
+                "summary": "Add player to squad",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Team ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Player details",
+                        "name": "player",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.PlayerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/api.PlayerResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "description": "Returns every registered webhook (secrets are not included)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.WebhooksResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Registers an endpoint that is POSTed league events: \"week.completed\", \"season.ended\" and \"champion.changed\" (plus \"ping\" on request). Each delivery is signed with the webhook secret: the X-League-Signature header is \"sha256=\" followed by the hex HMAC-SHA256 of the X-League-Timestamp header, a dot and the raw body. The secret is only returned in this response; a random one is generated if none is given. Failed deliveries are retried with exponential backoff.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Register webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook data",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/api.WebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "description": "Returns a registered webhook (the secret is not included)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.WebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes a webhook together with its delivery history; pending deliveries are not sent",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Returns the deliveries queued for a webhook, newest first, with their status, attempts and last error",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "delivered",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "Delivery status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of deliveries to return (1-100, default 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.WebhookDeliveriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/ping": {
+            "post": {
+                "description": "Queues a signed \"ping\" event for the webhook so the receiver can be tested. The delivery is sent in the background; check its status in the delivery history.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Ping webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookDelivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.APIInfoResponse": {
+            "type": "object",
+            "properties": {
                 "endpoints": {
                     "type": "object",
                     "additionalProperties": {
                         "type": "string"
                     }
                 },
-                "message": {
+                "message": {
+                    "type": "string",
+                    "example": "Insider League Simulator API"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "1.0.0"
+                }
+            }
+        },
+        "api.ArchiveRestoreResponse": {
+            "type": "object",
+            "properties": {
+                "match_events": {
+                    "type": "integer",
+                    "example": 84
+                },
+                "matches": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "message": {
+                    "type": "string",
+                    "example": "League archive restored"
+                },
+                "played": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "player_absences": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "players": {
+                    "description": "Current squad members, removed players not counted",
+                    "type": "integer",
+                    "example": 64
+                },
+                "predictions": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "standings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Standing"
+                    }
+                },
+                "teams": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "warnings": {
+                    "description": "Differences that may make the restored league behave differently",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "week": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "api.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Machine-readable error code",
+                    "type": "string",
+                    "example": "match_not_found"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "Detailed error information"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "Error message"
+                },
+                "failed_match_ids": {
+                    "description": "FailedMatchIDs lists the matches that could not be simulated when a week was rolled back",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        4
+                    ]
+                },
+                "timestamp": {
+                    "type": "string",
+                    "example": "2023-10-27 10:00:00"
+                }
+            }
+        },
+        "api.HealthCheckResponse": {
+            "type": "object",
+            "properties": {
+                "service": {
+                    "type": "string",
+                    "example": "insider-league-simulator"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "healthy"
+                },
+                "timestamp": {
+                    "$ref": "#/definitions/api.HealthCheckTimestamp"
+                }
+            }
+        },
+        "api.HealthCheckSecondsValue": {
+            "type": "object",
+            "properties": {
+                "value": {
+                    "type": "string",
+                    "example": "2023-10-27 10:00:00"
+                }
+            }
+        },
+        "api.HealthCheckTimestamp": {
+            "type": "object",
+            "properties": {
+                "unix": {
+                    "$ref": "#/definitions/api.HealthCheckUnixTimestamp"
+                }
+            }
+        },
+        "api.HealthCheckUnixTimestamp": {
+            "type": "object",
+            "properties": {
+                "seconds": {
+                    "$ref": "#/definitions/api.HealthCheckSecondsValue"
+                }
+            }
+        },
+        "api.ImportResponse": {
+            "type": "object",
+            "properties": {
+                "division": {
+                    "description": "Div column of the file, if present",
+                    "type": "string",
+                    "example": "E0"
+                },
+                "matches": {
+                    "type": "integer",
+                    "example": 380
+                },
+                "message": {
+                    "type": "string",
+                    "example": "League imported successfully"
+                },
+                "played": {
+                    "type": "integer",
+                    "example": 380
+                },
+                "season": {
+                    "description": "Season column of the file, if present",
+                    "type": "string",
+                    "example": "2023/2024"
+                },
+                "skipped_rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/importer.SkippedRow"
+                    }
+                },
+                "teams": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "weeks": {
+                    "type": "integer",
+                    "example": 38
+                }
+            }
+        },
+        "api.InitResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Database initialized successfully"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "New random fixture generated"
+                },
+                "timestamp": {
+                    "type": "string",
+                    "example": "2023-10-27 10:00:00"
+                }
+            }
+        },
+        "api.JobRequest": {
+            "type": "object"
+        },
+        "api.JobResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "params": {
+                    "type": "object"
+                },
+                "progress": {
+                    "type": "number",
+                    "example": 45
+                },
+                "result": {
+                    "description": "PredictionsListResponse or WeekSimulationResponse once succeeded; the weeks played so far for a stopped play_all job",
+                    "type": "object"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "running"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "predictions"
+                }
+            }
+        },
+        "api.JobsResponse": {
+            "type": "object",
+            "properties": {
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.JobResponse"
+                    }
+                },
+                "total_jobs": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "api.MatchDetailResponse": {
+            "type": "object",
+            "properties": {
+                "away_goals": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "away_team_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "away_team_name": {
+                    "type": "string",
+                    "example": "Team B"
+                },
+                "home_goals": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "home_team_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "home_team_name": {
+                    "type": "string",
+                    "example": "Team A"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "played": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "played_at": {
+                    "type": "string",
+                    "example": "2023-10-27T15:00:00Z"
+                },
+                "week": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "api.MatchEventResponse": {
+            "type": "object",
+            "properties": {
+                "assist_player_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "assist_player_name": {
+                    "type": "string",
+                    "example": "Dries Mertens"
+                },
+                "away_score": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "home_score": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "minute": {
+                    "type": "integer",
+                    "example": 23
+                },
+                "player_id": {
+                    "type": "integer",
+                    "example": 9
+                },
+                "player_name": {
+                    "type": "string",
+                    "example": "Mauro Icardi"
+                },
+                "team_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "team_name": {
+                    "type": "string",
+                    "example": "Team A"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "goal"
+                }
+            }
+        },
+        "api.MatchEventsResponse": {
+            "type": "object",
+            "properties": {
+                "away_goals": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "away_team_name": {
+                    "type": "string",
+                    "example": "Team B"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.MatchEventResponse"
+                    }
+                },
+                "half_time_away_goals": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "half_time_home_goals": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "home_goals": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "home_team_name": {
+                    "type": "string",
+                    "example": "Team A"
+                },
+                "match_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total_events": {
+                    "type": "integer",
+                    "example": 14
+                },
+                "week": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "api.MatchSimulationResponse": {
+            "type": "object",
+            "properties": {
+                "away_goals": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "away_lambda": {
+                    "type": "number",
+                    "example": 1.12
+                },
+                "away_team_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "away_team_name": {
+                    "type": "string",
+                    "example": "Team B"
+                },
+                "home_goals": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "home_lambda": {
+                    "type": "number",
+                    "example": 1.85
+                },
+                "home_team_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "home_team_name": {
+                    "type": "string",
+                    "example": "Team A"
+                },
+                "match_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "week": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "api.MatchesResponse": {
+            "type": "object",
+            "properties": {
+                "matches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.MatchDetailResponse"
+                    }
+                },
+                "total_matches": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "week": {
+                    "type": "string",
+                    "example": "3"
+                }
+            }
+        },
+        "api.PlayUntilResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "League played until week 4"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.MatchSimulationResponse"
+                    }
+                },
+                "standings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Standing"
+                    }
+                },
+                "week": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "api.PlayerRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "position"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Mauro Icardi"
+                },
+                "position": {
+                    "type": "string",
+                    "enum": [
+                        "GK",
+                        "DF",
+                        "MF",
+                        "FW"
+                    ],
+                    "example": "FW"
+                },
+                "rating": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1,
+                    "example": 85
+                },
+                "scoring_share": {
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 0.25
+                },
+                "shirt_number": {
+                    "type": "integer",
+                    "example": 9
+                }
+            }
+        },
+        "api.PlayerResponse": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "matches_out": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Mauro Icardi"
+                },
+                "position": {
+                    "type": "string",
+                    "example": "FW"
+                },
+                "rating": {
+                    "type": "integer",
+                    "example": 85
+                },
+                "scoring_share": {
+                    "type": "number",
+                    "example": 0.25
+                },
+                "shirt_number": {
+                    "type": "integer",
+                    "example": 9
+                },
+                "team_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "api.PredictionResult": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-10-27 10:00:00"
+                },
+                "probability": {
+                    "type": "number",
+                    "example": 0.25
+                },
+                "team_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "team_name": {
+                    "type": "string",
+                    "example": "Team A"
+                }
+            }
+        },
+        "api.PredictionsListResponse": {
+            "type": "object",
+            "properties": {
+                "method": {
+                    "type": "string",
+                    "example": "Monte Carlo Simulation (2,000 iterations)"
+                },
+                "predictions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.PredictionResult"
+                    }
+                },
+                "total_teams": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "week": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "api.RestoreResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Snapshot 2 restored"
+                },
+                "snapshot": {
+                    "$ref": "#/definitions/models.LeagueSnapshot"
+                },
+                "standings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Standing"
+                    }
+                }
+            }
+        },
+        "api.RewindResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "League rewound to week 3"
+                },
+                "snapshot": {
+                    "description": "Snapshot taken before the rewind, restore it to undo",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LeagueSnapshot"
+                        }
+                    ]
+                },
+                "standings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Standing"
+                    }
+                },
+                "week": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "api.ScorerResult": {
+            "type": "object",
+            "properties": {
+                "assists": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "goals": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "player_id": {
+                    "type": "integer",
+                    "example": 9
+                },
+                "player_name": {
+                    "type": "string",
+                    "example": "Mauro Icardi"
+                },
+                "team_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "team_name": {
+                    "type": "string",
+                    "example": "Team A"
+                }
+            }
+        },
+        "api.SeasonAnalysisRequest": {
+            "type": "object",
+            "properties": {
+                "seasons": {
+                    "description": "Seasons to simulate; 0 uses 10000",
+                    "type": "integer",
+                    "maximum": 100000,
+                    "minimum": 1,
+                    "example": 10000
+                },
+                "seed": {
+                    "description": "Random seed; 0 picks a random seed",
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "api.SnapshotRequest": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Before the derby"
+                }
+            }
+        },
+        "api.SnapshotsResponse": {
+            "type": "object",
+            "properties": {
+                "snapshots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LeagueSnapshot"
+                    }
+                },
+                "total_snapshots": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "api.SquadResponse": {
+            "type": "object",
+            "properties": {
+                "attack_factor": {
+                    "type": "number",
+                    "example": 0.95
+                },
+                "defense_factor": {
+                    "type": "number",
+                    "example": 1
+                },
+                "players": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.PlayerResponse"
+                    }
+                },
+                "team_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "team_name": {
+                    "type": "string",
+                    "example": "Team A"
+                },
+                "total_players": {
+                    "type": "integer",
+                    "example": 16
+                }
+            }
+        },
+        "api.StandingsResponse": {
+            "type": "object",
+            "properties": {
+                "standings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Standing"
+                    }
+                },
+                "total_teams": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "api.TopScorersResponse": {
+            "type": "object",
+            "properties": {
+                "scorers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.ScorerResult"
+                    }
+                },
+                "total_scorers": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "api.WebhookDeliveriesResponse": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WebhookDelivery"
+                    }
+                },
+                "total_deliveries": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "webhook_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "api.WebhookRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "description": "Empty subscribes to every event",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "week.completed",
+                        "season.ended"
+                    ]
+                },
+                "secret": {
+                    "description": "Generated when empty",
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "s3cr3t"
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2048,
+                    "example": "https://example.com/hooks/league"
+                }
+            }
+        },
+        "api.WebhookResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "week.completed",
+                        "season.ended"
+                    ]
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "secret": {
+                    "description": "Only returned when the webhook is registered",
+                    "type": "string",
+                    "example": "s3cr3t"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/league"
+                }
+            }
+        },
+        "api.WebhooksResponse": {
+            "type": "object",
+            "properties": {
+                "total_webhooks": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "webhooks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.WebhookResponse"
+                    }
+                }
+            }
+        },
+        "api.WeekSimulationResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Next week successfully simulated"
+                },
+                "predictions": {
+                    "description": "Only when predictions=true and matches remain",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/api.PredictionsListResponse"
+                        }
+                    ]
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.MatchSimulationResponse"
+                    }
+                },
+                "standings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Standing"
+                    }
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "weeks_played": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        4
+                    ]
+                }
+            }
+        },
+        "archive.Archive": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "When the archive was created",
+                    "type": "string"
+                },
+                "format": {
+                    "description": "Always \"insider-league-archive\"",
                     "type": "string",
-                    "example": "Insider League Simulator API"
+                    "example": "insider-league-archive"
+                },
+                "match_events": {
+                    "description": "Timelines of the played matches",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MatchEvent"
+                    }
+                },
+                "matches": {
+                    "description": "The fixture with its results",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/archive.Match"
+                    }
+                },
+                "model": {
+                    "description": "Match model parameters of the archiving instance",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/archive.Model"
+                        }
+                    ]
+                },
+                "player_absences": {
+                    "description": "Injuries and suspensions",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlayerAbsence"
+                    }
+                },
+                "predictions": {
+                    "description": "Championship predictions of all weeks",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Prediction"
+                    }
+                },
+                "seed": {
+                    "description": "Random state of the matches; nil if no match model seed was chosen",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/archive.Seed"
+                        }
+                    ]
+                },
+                "teams": {
+                    "description": "Teams with their statistics and squads",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/archive.Team"
+                    }
                 },
                 "version": {
-                    "type": "string",
-                    "example": "1.0.0"
+                    "description": "Layout version of the archive",
+                    "type": "integer",
+                    "example": 1
+                },
+                "week": {
+                    "description": "Last completed week",
+                    "type": "integer",
+                    "example": 3
                 }
             }
         },
-        "api.ErrorResponse": {
+        "archive.Match": {
             "type": "object",
             "properties": {
-                "detail": {
-                    "type": "string",
-                    "example": "Detailed error information"
+                "away_goals": {
+                    "description": "nil if the match has not been played",
+                    "type": "integer",
+                    "example": 1
                 },
-                "error": {
-                    "type": "string",
-                    "example": "Error message"
+                "away_team_id": {
+                    "type": "integer",
+                    "example": 2
                 },
-                "timestamp": {
+                "home_goals": {
+                    "description": "nil if the match has not been played",
+                    "type": "integer",
+                    "example": 2
+                },
+                "home_team_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "played_at": {
+                    "type": "string"
+                },
+                "week": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "archive.Model": {
+            "type": "object",
+            "properties": {
+                "form_factor_max": {
+                    "type": "number",
+                    "example": 1.2
+                },
+                "form_factor_min": {
+                    "type": "number",
+                    "example": 0.8
+                },
+                "home_advantage": {
+                    "description": "Largest random home advantage",
+                    "type": "number",
+                    "example": 0.15
+                },
+                "league_average": {
+                    "description": "Goals per team per match (λ_league)",
+                    "type": "number",
+                    "example": 1.5
+                },
+                "max_goals": {
+                    "description": "Most goals of a team in a match",
+                    "type": "integer",
+                    "example": 8
+                },
+                "max_lambda": {
+                    "type": "number",
+                    "example": 4
+                },
+                "min_lambda": {
+                    "type": "number",
+                    "example": 0.1
+                },
+                "name": {
                     "type": "string",
-                    "example": "2023-10-27 10:00:00"
+                    "example": "poisson"
                 }
             }
         },
-        "api.HealthCheckResponse": {
+        "archive.Player": {
             "type": "object",
             "properties": {
-                "service": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
                     "type": "string",
-                    "example": "insider-league-simulator"
+                    "example": "Fernando Muslera"
                 },
-                "status": {
+                "position": {
                     "type": "string",
-                    "example": "healthy"
+                    "example": "GK"
                 },
-                "timestamp": {
-                    "$ref": "#/definitions/api.HealthCheckTimestamp"
+                "rating": {
+                    "type": "integer",
+                    "example": 78
+                },
+                "removed_at": {
+                    "description": "When the player was removed from the squad; nil for current players",
+                    "type": "string"
+                },
+                "scoring_share": {
+                    "type": "number",
+                    "example": 0
+                },
+                "shirt_number": {
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "api.HealthCheckSecondsValue": {
+        "archive.Seed": {
             "type": "object",
             "properties": {
+                "matches": {
+                    "description": "Matches simulated with the seed so far",
+                    "type": "integer",
+                    "example": 6
+                },
                 "value": {
+                    "description": "Seed chosen when the league was created",
+                    "type": "integer",
+                    "example": 1718040000000000000
+                }
+            }
+        },
+        "archive.Team": {
+            "type": "object",
+            "properties": {
+                "attack": {
+                    "type": "integer",
+                    "example": 80
+                },
+                "defense": {
+                    "type": "integer",
+                    "example": 75
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
                     "type": "string",
-                    "example": "2023-10-27 10:00:00"
+                    "example": "Galatasaray"
+                },
+                "players": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/archive.Player"
+                    }
+                },
+                "stats": {
+                    "description": "nil if the team has no statistics",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/archive.TeamStats"
+                        }
+                    ]
                 }
             }
         },
-        "api.HealthCheckTimestamp": {
+        "archive.TeamStats": {
             "type": "object",
             "properties": {
-                "unix": {
-                    "$ref": "#/definitions/api.HealthCheckUnixTimestamp"
+                "attack_strength": {
+                    "type": "number",
+                    "example": 1.07
+                },
+                "avg_conceded": {
+                    "type": "number",
+                    "example": 0.25
+                },
+                "avg_scored": {
+                    "type": "number",
+                    "example": 0.8
+                },
+                "defense_strength": {
+                    "type": "number",
+                    "example": 1
+                },
+                "drawn": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "goals_against": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "goals_for": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "lost": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "played": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "points": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "won": {
+                    "type": "integer",
+                    "example": 2
                 }
             }
         },
-        "api.HealthCheckUnixTimestamp": {
+        "export.League": {
             "type": "object",
             "properties": {
-                "seconds": {
-                    "$ref": "#/definitions/api.HealthCheckSecondsValue"
+                "exported_at": {
+                    "type": "string"
+                },
+                "matches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/export.Match"
+                    }
+                },
+                "predictions": {
+                    "description": "Predictions of every week, oldest week first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/export.Prediction"
+                    }
+                },
+                "standings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Standing"
+                    }
+                },
+                "teams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/export.Team"
+                    }
                 }
             }
         },
-        "api.InitResponse": {
+        "export.Match": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Database initialized successfully"
+                "away_goals": {
+                    "description": "nil if not played",
+                    "type": "integer"
                 },
-                "note": {
-                    "type": "string",
-                    "example": "New random fixture generated"
+                "away_team_id": {
+                    "type": "integer"
                 },
-                "timestamp": {
-                    "type": "string",
-                    "example": "2023-10-27 10:00:00"
+                "away_team_name": {
+                    "type": "string"
+                },
+                "home_goals": {
+                    "description": "nil if not played",
+                    "type": "integer"
+                },
+                "home_team_id": {
+                    "type": "integer"
+                },
+                "home_team_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "played_at": {
+                    "description": "nil if not played",
+                    "type": "string"
+                },
+                "week": {
+                    "type": "integer"
+                }
+            }
+        },
+        "export.Prediction": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "probability": {
+                    "description": "Championship probability in percent",
+                    "type": "number"
+                },
+                "team_id": {
+                    "type": "integer"
+                },
+                "team_name": {
+                    "type": "string"
+                },
+                "week": {
+                    "type": "integer"
                 }
             }
         },
-        "api.MatchDetailResponse": {
+        "export.Team": {
             "type": "object",
             "properties": {
-                "away_goals": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "away_team_id": {
-                    "type": "integer",
-                    "example": 2
-                },
-                "away_team_name": {
-                    "type": "string",
-                    "example": "Team B"
-                },
-                "home_goals": {
-                    "type": "integer",
-                    "example": 2
-                },
-                "home_team_id": {
-                    "type": "integer",
-                    "example": 1
+                "attack": {
+                    "description": "Offensive strength of the team",
+                    "type": "integer"
                 },
-                "home_team_name": {
-                    "type": "string",
-                    "example": "Team A"
+                "defense": {
+                    "description": "Defensive strength of the team",
+                    "type": "integer"
                 },
                 "id": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "played": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "played_at": {
-                    "type": "string",
-                    "example": "2023-10-27T15:00:00Z"
+                    "type": "integer"
                 },
-                "week": {
-                    "type": "integer",
-                    "example": 1
+                "name": {
+                    "type": "string"
                 }
             }
         },
-        "api.MatchesResponse": {
+        "importer.SkippedRow": {
             "type": "object",
             "properties": {
-                "matches": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/api.MatchDetailResponse"
-                    }
-                },
-                "total_matches": {
+                "line": {
                     "type": "integer",
-                    "example": 10
+                    "example": 14
                 },
-                "week": {
+                "reason": {
                     "type": "string",
-                    "example": "3"
+                    "example": "invalid home goals \"x\""
                 }
             }
         },
-        "api.PredictionResult": {
+        "models.LeagueSnapshot": {
             "type": "object",
             "properties": {
                 "created_at": {
-                    "type": "string",
-                    "example": "2023-10-27 10:00:00"
+                    "description": "When the snapshot was taken",
+                    "type": "string"
                 },
-                "probability": {
-                    "type": "number",
-                    "example": 0.25
+                "id": {
+                    "description": "Unique ID of the snapshot",
+                    "type": "integer"
                 },
-                "team_id": {
-                    "type": "integer",
-                    "example": 1
+                "label": {
+                    "description": "Description of why the snapshot was taken",
+                    "type": "string"
                 },
-                "team_name": {
-                    "type": "string",
-                    "example": "Team A"
+                "week": {
+                    "description": "Last completed week when the snapshot was taken",
+                    "type": "integer"
                 }
             }
         },
-        "api.PredictionsListResponse": {
+        "models.MatchEvent": {
             "type": "object",
             "properties": {
-                "method": {
-                    "type": "string",
-                    "example": "Monte Carlo Simulation (2,000 iterations)"
+                "assist_player_id": {
+                    "description": "Player who assisted a goal",
+                    "type": "integer"
                 },
-                "predictions": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/api.PredictionResult"
-                    }
+                "away_score": {
+                    "description": "Away team's score after the event",
+                    "type": "integer"
                 },
-                "total_teams": {
-                    "type": "integer",
-                    "example": 4
+                "created_at": {
+                    "description": "Date and time the event was recorded",
+                    "type": "string"
                 },
-                "week": {
-                    "type": "integer",
-                    "example": 4
+                "home_score": {
+                    "description": "Home team's score after the event",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "Unique ID of the event",
+                    "type": "integer"
+                },
+                "match_id": {
+                    "description": "ID of the match the event belongs to",
+                    "type": "integer"
+                },
+                "minute": {
+                    "description": "Minute of the match in which the event occurred (1-90)",
+                    "type": "integer"
+                },
+                "player_id": {
+                    "description": "Scorer of a goal, player shown a card or injured player",
+                    "type": "integer"
+                },
+                "team_id": {
+                    "description": "Team the event belongs to (nil for half_time and full_time)",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "Event type (goal, yellow_card, red_card, substitution, injury, half_time, full_time)",
+                    "type": "string"
                 }
             }
         },
-        "api.SimulationResponse": {
+        "models.PlayerAbsence": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Operation successful"
+                "created_at": {
+                    "description": "Date and time the absence was recorded",
+                    "type": "string"
                 },
-                "success": {
-                    "type": "boolean",
-                    "example": true
+                "id": {
+                    "description": "Unique ID of the absence",
+                    "type": "integer"
+                },
+                "match_id": {
+                    "description": "Match in which the injury or suspension was incurred",
+                    "type": "integer"
+                },
+                "matches": {
+                    "description": "Number of team matches the player misses",
+                    "type": "integer"
+                },
+                "player_id": {
+                    "description": "ID of the unavailable player",
+                    "type": "integer"
+                },
+                "reason": {
+                    "description": "Reason of the absence (injury, suspension)",
+                    "type": "string"
+                },
+                "team_id": {
+                    "description": "ID of the player's team",
+                    "type": "integer"
+                },
+                "week": {
+                    "description": "Week of that match",
+                    "type": "integer"
                 }
             }
         },
-        "api.StandingsResponse": {
+        "models.Prediction": {
             "type": "object",
             "properties": {
-                "standings": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Standing"
-                    }
+                "created_at": {
+                    "description": "Date and time the prediction was made",
+                    "type": "string"
                 },
-                "total_teams": {
-                    "type": "integer",
-                    "example": 4
+                "id": {
+                    "description": "Unique ID of the prediction",
+                    "type": "integer"
+                },
+                "probability": {
+                    "description": "Probability of winning the championship (percentage)",
+                    "type": "number"
+                },
+                "team_id": {
+                    "description": "ID of the team",
+                    "type": "integer"
+                },
+                "week": {
+                    "description": "Week in which the prediction was made",
+                    "type": "integer"
                 }
             }
         },
@@ -528,6 +3175,200 @@ const docTemplate = `{
                     "type": "integer"
                 }
             }
+        },
+        "models.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Number of delivery attempts made",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "When the event was queued",
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "description": "When the receiver accepted the delivery",
+                    "type": "string"
+                },
+                "event": {
+                    "description": "Event type, e.g. week.completed",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique ID of the delivery",
+                    "type": "integer"
+                },
+                "last_error": {
+                    "description": "Error of the last failed attempt",
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "description": "When the next attempt is due",
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "JSON request body sent to the webhook",
+                    "type": "string"
+                },
+                "response_status": {
+                    "description": "HTTP status of the last attempt",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "pending, delivered or failed",
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "description": "ID of the receiving webhook",
+                    "type": "integer"
+                }
+            }
+        },
+        "season.PointsFrequency": {
+            "type": "object",
+            "properties": {
+                "percentage": {
+                    "type": "number",
+                    "example": 14.8
+                },
+                "points": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "seasons": {
+                    "type": "integer",
+                    "example": 1480
+                }
+            }
+        },
+        "season.Report": {
+            "type": "object",
+            "properties": {
+                "average_away_goals": {
+                    "description": "Away goals per match",
+                    "type": "number",
+                    "example": 1.16
+                },
+                "average_goals": {
+                    "description": "Goals per match",
+                    "type": "number",
+                    "example": 2.61
+                },
+                "average_home_goals": {
+                    "description": "Home goals per match",
+                    "type": "number",
+                    "example": 1.45
+                },
+                "away_win_rate": {
+                    "description": "Percentage of away wins",
+                    "type": "number",
+                    "example": 29.3
+                },
+                "draw_rate": {
+                    "description": "Percentage of draws",
+                    "type": "number",
+                    "example": 25.9
+                },
+                "duration_ms": {
+                    "description": "Time the simulation took",
+                    "type": "integer",
+                    "example": 184
+                },
+                "home_win_rate": {
+                    "description": "Percentage of home wins",
+                    "type": "number",
+                    "example": 44.8
+                },
+                "matches_per_season": {
+                    "description": "Matches played in each season",
+                    "type": "integer",
+                    "example": 12
+                },
+                "seasons": {
+                    "description": "Simulated seasons",
+                    "type": "integer",
+                    "example": 10000
+                },
+                "seed": {
+                    "description": "Random seed; the same seed reproduces the report",
+                    "type": "integer",
+                    "example": 42
+                },
+                "teams": {
+                    "description": "Most likely champion first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/season.TeamReport"
+                    }
+                }
+            }
+        },
+        "season.TeamReport": {
+            "type": "object",
+            "properties": {
+                "average_goals_against": {
+                    "description": "Goals conceded per season",
+                    "type": "number",
+                    "example": 5.2
+                },
+                "average_goals_for": {
+                    "description": "Goals scored per season",
+                    "type": "number",
+                    "example": 8.1
+                },
+                "average_points": {
+                    "description": "Points per season",
+                    "type": "number",
+                    "example": 11.4
+                },
+                "max_points": {
+                    "description": "Most points in a season",
+                    "type": "integer",
+                    "example": 18
+                },
+                "min_points": {
+                    "description": "Fewest points in a season",
+                    "type": "integer",
+                    "example": 1
+                },
+                "points_distribution": {
+                    "description": "Seasons per points total, fewest points first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/season.PointsFrequency"
+                    }
+                },
+                "position_odds": {
+                    "description": "Percentage of seasons finished in each position, first place first",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "team_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "team_name": {
+                    "type": "string",
+                    "example": "Galatasaray"
+                },
+                "title_odds": {
+                    "description": "Percentage of seasons won",
+                    "type": "number",
+                    "example": 61.2
+                }
+            }
+        },
+        "stream.Event": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "type": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
@@ -0,0 +1,42 @@
+// Package api - Season analysis handlers
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/season"
+)
+
+// SimulateSeasons simulates the full season many times and reports the distributions
+// @Summary Simulate seasons
+// @Description Plays the whole fixture from the season-start team strengths thousands of times with the Poisson match model and reports title odds, finishing positions, points distributions, goals per match and home/draw/away rates. Nothing is saved; the same seed reproduces the same report.
+// @Tags analysis
+// @Accept json
+// @Produce json
+// @Param analysis body SeasonAnalysisRequest false "Number of seasons and random seed"
+// @Success 200 {object} season.Report
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
+// @Router /analysis/seasons [post]
+func (s *Server) SimulateSeasons(c *gin.Context) {
+	var request SeasonAnalysisRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			respondInvalid(c, "Invalid season analysis request", err.Error())
+			return
+		}
+	}
+	if request.Seasons == 0 {
+		request.Seasons = season.DefaultSeasons
+	}
+
+	report, err := season.NewSeasonSimulator(s.store).SimulateSeasons(c.Request.Context(), request.Seasons, request.Seed, nil)
+	if err != nil {
+		respondError(c, "Could not simulate seasons", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,89 @@
+// Package api - League archive handlers
+package api
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tarikbacak/insider-league-simulator/internal/archive"
+	"github.com/tarikbacak/insider-league-simulator/internal/db"
+)
+
+// GetArchive downloads the complete state of the league
+// @Summary Download league archive
+// @Description Downloads a self-describing JSON archive of the complete league: teams with their ratings, statistics and squads, the fixture with its results, match timelines, injuries/suspensions, the predictions of every week, the match model parameters and the random seed of the league. Records keep their IDs. POST the archive to /archive/restore, in this or another instance, to restore the league exactly; with the same match model parameters, matches played afterwards have the same results in both instances. Championship predictions are Monte Carlo estimates and vary between runs.
+// @Tags archive
+// @Produce json
+// @Success 200 {object} archive.Archive
+// @Failure 500 {object} ErrorResponse
+// @Router /archive [get]
+func (s *Server) GetArchive(c *gin.Context) {
+	leagueArchive, err := archive.Build(c.Request.Context(), s.store)
+	if err != nil {
+		respondError(c, "Could not archive league", err)
+		return
+	}
+	var body bytes.Buffer
+	if err := archive.Write(&body, leagueArchive); err != nil {
+		respondError(c, "Could not archive league", err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="league-archive-`+time.Now().Format("20060102")+`.json"`)
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body.Bytes())
+}
+
+// RestoreArchive replaces the league with the content of a league archive
+// @Summary Restore league archive
+// @Description Replaces the league with a JSON archive downloaded from /archive, sent as the "file" field of a multipart form or as the request body. Teams, statistics, squads, results, timelines, injuries/suspensions and predictions are restored with their IDs, and the random seed so that matches played from now on have the same results as in the archiving instance. Warnings lists differences, such as other match model parameters or a missing seed, that make matches played from now on behave differently. Like /init, the current league and its snapshots are deleted.
+// @Tags archive
+// @Accept json,multipart/form-data
+// @Produce json
+// @Param file formData file false "League archive"
+// @Success 200 {object} ArchiveRestoreResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /archive/restore [post]
+func (s *Server) RestoreArchive(c *gin.Context) {
+	file, err := importFile(c)
+	if err != nil {
+		respondImportReadError(c, err)
+		return
+	}
+	defer file.Close()
+
+	leagueArchive, err := archive.Read(file)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		respondImportReadError(c, err)
+		return
+	}
+	if err != nil {
+		respondError(c, "Could not read league archive", err)
+		return
+	}
+
+	summary, err := archive.Restore(c.Request.Context(), s.store, leagueArchive)
+	if err != nil {
+		respondError(c, "Could not restore league archive", err)
+		return
+	}
+
+	s.publishLeagueReset("archive")
+
+	standings, err := db.CalculateStandings(c.Request.Context(), s.store)
+	if err != nil {
+		respondError(c, "Could not calculate standings", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ArchiveRestoreResponse{
+		Message:   "League archive restored",
+		Summary:   *summary,
+		Standings: standings,
+	})
+}
@@ -0,0 +1,126 @@
+// Package api - Error responses and the mapping of errors to HTTP status codes
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tarikbacak/insider-league-simulator/internal/archive"
+	"github.com/tarikbacak/insider-league-simulator/internal/db"
+	"github.com/tarikbacak/insider-league-simulator/internal/importer"
+	"github.com/tarikbacak/insider-league-simulator/internal/jobs"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/base"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/season"
+)
+
+// Machine-readable error codes returned in ErrorResponse.Code
+const (
+	CodeInvalidRequest       = "invalid_request"
+	CodeInternalError        = "internal_error"
+	CodeSeasonComplete       = "season_complete"
+	CodeNoFixtures           = "no_fixtures"
+	CodeInvalidImportFile    = "invalid_import_file"
+	CodeFileTooLarge         = "file_too_large"
+	CodeInvalidArchive       = "invalid_archive"
+	CodeTeamNotFound         = "team_not_found"
+	CodeMatchNotFound        = "match_not_found"
+	CodePlayerNotFound       = "player_not_found"
+	CodeSnapshotNotFound     = "snapshot_not_found"
+	CodeWebhookNotFound      = "webhook_not_found"
+	CodeJobNotFound          = "job_not_found"
+	CodeUnknownJobType       = "unknown_job_type"
+	CodeJobFinished          = "job_finished"
+	CodeJobQueueFull         = "job_queue_full"
+	CodeRequestTimeout       = "request_timeout"
+	CodeRequestCancelled     = "request_cancelled"
+	CodeMatchAlreadyPlayed   = "match_already_played"
+	CodeSnapshotMismatch     = "snapshot_mismatch"
+	CodeWeekSimulationFailed = "week_simulation_failed"
+	CodeIdempotencyKeyReused = "idempotency_key_reused"
+	CodeRequestInProgress    = "request_in_progress"
+)
+
+// statusClientClosedRequest is the non-standard status used when the client
+// disconnected before the response was ready
+const statusClientClosedRequest = 499
+
+// errorMappings maps sentinel errors of the simulator, db, importer, archive and jobs packages to HTTP responses
+// Errors are matched with errors.Is in order; unmatched errors are internal errors.
+var errorMappings = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{base.ErrSeasonComplete, http.StatusBadRequest, CodeSeasonComplete},
+	{season.ErrNoFixtures, http.StatusBadRequest, CodeNoFixtures},
+	{importer.ErrMissingColumns, http.StatusBadRequest, CodeInvalidImportFile},
+	{importer.ErrNoMatches, http.StatusBadRequest, CodeInvalidImportFile},
+	{archive.ErrInvalidArchive, http.StatusBadRequest, CodeInvalidArchive},
+	{db.ErrTeamNotFound, http.StatusNotFound, CodeTeamNotFound},
+	{db.ErrMatchNotFound, http.StatusNotFound, CodeMatchNotFound},
+	{db.ErrPlayerNotFound, http.StatusNotFound, CodePlayerNotFound},
+	{db.ErrSnapshotNotFound, http.StatusNotFound, CodeSnapshotNotFound},
+	{db.ErrWebhookNotFound, http.StatusNotFound, CodeWebhookNotFound},
+	{db.ErrJobNotFound, http.StatusNotFound, CodeJobNotFound},
+	{jobs.ErrUnknownJobType, http.StatusBadRequest, CodeUnknownJobType},
+	{base.ErrMatchAlreadyPlayed, http.StatusConflict, CodeMatchAlreadyPlayed},
+	{db.ErrSnapshotMismatch, http.StatusConflict, CodeSnapshotMismatch},
+	{jobs.ErrJobFinished, http.StatusConflict, CodeJobFinished},
+	{jobs.ErrQueueFull, http.StatusServiceUnavailable, CodeJobQueueFull},
+}
+
+// respondError writes the error response for err with the status code and error
+// code it maps to. If a simulation week was rolled back, the IDs of the matches
+// that failed are included. Errors caused by the request context expiring or
+// being cancelled are reported as such, even if the database driver returned
+// its own error for the interrupted query.
+func respondError(c *gin.Context, message string, err error) {
+	response := ErrorResponse{
+		Error:  message,
+		Code:   CodeInternalError,
+		Detail: err.Error(),
+	}
+	status := http.StatusInternalServerError
+
+	// A rolled back week wraps the errors of its matches, so it is checked after
+	// the context errors those matches may have failed with
+	ctxErr := c.Request.Context().Err()
+	var weekErr *base.WeekSimulationError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(ctxErr, context.DeadlineExceeded):
+		status = http.StatusGatewayTimeout
+		response.Code = CodeRequestTimeout
+	case errors.Is(err, context.Canceled) || errors.Is(ctxErr, context.Canceled):
+		status = statusClientClosedRequest
+		response.Code = CodeRequestCancelled
+	case errors.As(err, &weekErr):
+		response.Code = CodeWeekSimulationFailed
+		response.FailedMatchIDs = weekErr.FailedMatchIDs
+	default:
+		for _, mapping := range errorMappings {
+			if errors.Is(err, mapping.err) {
+				status = mapping.status
+				response.Code = mapping.code
+				break
+			}
+		}
+	}
+
+	c.AbortWithStatusJSON(status, response)
+}
+
+// respondInvalid writes a 400 response for an invalid request parameter or body
+func respondInvalid(c *gin.Context, message, detail string) {
+	writeError(c, http.StatusBadRequest, CodeInvalidRequest, message, detail)
+}
+
+// writeError writes an error response with an explicit status and error code
+func writeError(c *gin.Context, status int, code, message, detail string) {
+	c.AbortWithStatusJSON(status, ErrorResponse{
+		Error:  message,
+		Code:   code,
+		Detail: detail,
+	})
+}
@@ -0,0 +1,58 @@
+// Package api - League export handler
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tarikbacak/insider-league-simulator/internal/export"
+)
+
+// ExportLeague downloads the league as JSON, CSV or an Excel workbook
+// @Summary Export the league
+// @Description Downloads the teams, fixtures, results, standings and predictions history. json returns the complete league as one document and xlsx an Excel workbook with one worksheet per dataset; csv returns the dataset selected by the dataset parameter.
+// @Tags export
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "Export format" Enums(json, csv, xlsx) default(json)
+// @Param dataset query string false "Dataset of a CSV export" Enums(teams, matches, results, standings, predictions) default(matches)
+// @Success 200 {object} export.League
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /export [get]
+func (s *Server) ExportLeague(c *gin.Context) {
+	format := c.DefaultQuery("format", export.FormatJSON)
+	if !slices.Contains(export.Formats, format) {
+		respondInvalid(c, "Invalid export format", fmt.Sprintf("format must be one of %s", strings.Join(export.Formats, ", ")))
+		return
+	}
+	dataset := c.DefaultQuery("dataset", export.DatasetMatches)
+	if format == export.FormatCSV && !slices.Contains(export.Datasets, dataset) {
+		respondInvalid(c, "Invalid dataset", fmt.Sprintf("dataset must be one of %s", strings.Join(export.Datasets, ", ")))
+		return
+	}
+
+	league, err := export.Build(c.Request.Context(), s.store)
+	if err != nil {
+		respondError(c, "Could not export league", err)
+		return
+	}
+	// Written to a buffer first so that a failed export still gets an error response
+	var body bytes.Buffer
+	if err := export.Write(&body, league, format, dataset); err != nil {
+		respondError(c, "Could not export league", err)
+		return
+	}
+
+	filename := "league-" + time.Now().Format("20060102")
+	if format == export.FormatCSV {
+		filename += "-" + dataset
+	}
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+"."+format))
+	c.Data(http.StatusOK, export.ContentType(format), body.Bytes())
+}
@@ -2,15 +2,26 @@
 package api
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/tarikbacak/insider-league-simulator/internal/db"
 	"github.com/tarikbacak/insider-league-simulator/internal/models"
-	"github.com/tarikbacak/insider-league-simulator/internal/simulator"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/base"
+	simModels "github.com/tarikbacak/insider-league-simulator/internal/simulator/models"
+	"github.com/tarikbacak/insider-league-simulator/internal/stream"
+)
+
+// Championship prediction settings shared by the prediction endpoints
+const (
+	predictionIterations = 2000 // Reduced from 10000 for faster predictions
+	predictionMethod     = "Monte Carlo Simulation (2,000 iterations)"
 )
 
 // GetStandings returns the current league standings
@@ -21,77 +32,13 @@ import (
 // @Success 200 {object} StandingsResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /standings [get]
-func GetStandings(c *gin.Context) {
-	database := db.GetDB()
-
-	var teams []models.Team
-	// Fetch teams and their related matches
-	err := database.
-		Preload("HomeGames", "home_goals IS NOT NULL AND away_goals IS NOT NULL").
-		Preload("AwayGames", "home_goals IS NOT NULL AND away_goals IS NOT NULL").
-		Find(&teams).Error
+func (s *Server) GetStandings(c *gin.Context) {
+	standings, err := db.CalculateStandings(c.Request.Context(), s.store)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:  "Could not calculate standings",
-			Detail: err.Error(),
-		})
+		respondError(c, "Could not calculate standings", err)
 		return
 	}
 
-	var standings []models.Standing
-	for _, team := range teams {
-		standing := models.Standing{
-			TeamID:   team.ID,
-			TeamName: team.Name,
-		}
-
-		// Calculate match statistics
-		for _, match := range team.HomeGames {
-			if match.HomeGoals != nil && match.AwayGoals != nil {
-				standing.Played++
-				standing.GoalsFor += uint(*match.HomeGoals) // Assuming HomeGoals is *int or similar, casting to uint
-				standing.GoalsAgainst += uint(*match.AwayGoals)
-				if *match.HomeGoals > *match.AwayGoals {
-					standing.Won++
-					standing.Points += 3
-				} else if *match.HomeGoals < *match.AwayGoals {
-					standing.Lost++
-				} else {
-					standing.Drawn++
-					standing.Points++
-				}
-			}
-		}
-
-		for _, match := range team.AwayGames {
-			if match.HomeGoals != nil && match.AwayGoals != nil {
-				standing.Played++
-				standing.GoalsFor += uint(*match.AwayGoals) // Team was away, so AwayGoals are their scored goals
-				standing.GoalsAgainst += uint(*match.HomeGoals)
-				if *match.AwayGoals > *match.HomeGoals {
-					standing.Won++
-					standing.Points += 3
-				} else if *match.AwayGoals < *match.HomeGoals {
-					standing.Lost++
-				} else {
-					standing.Drawn++
-					standing.Points++
-				}
-			}
-		}
-
-		standing.GoalDifference = int(standing.GoalsFor) - int(standing.GoalsAgainst)
-		standings = append(standings, standing)
-	}
-
-	// Sort standings (by points and goal difference)
-	sort.SliceStable(standings, func(i, j int) bool {
-		if standings[i].Points != standings[j].Points {
-			return standings[i].Points > standings[j].Points
-		}
-		return standings[i].GoalDifference > standings[j].GoalDifference
-	})
-
 	c.JSON(http.StatusOK, StandingsResponse{
 		Standings:  standings,
 		TotalTeams: len(standings),
@@ -108,22 +55,17 @@ func GetStandings(c *gin.Context) {
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /matches [get]
-func GetMatches(c *gin.Context) {
-	database := db.GetDB()
+func (s *Server) GetMatches(c *gin.Context) {
+	ctx := c.Request.Context()
 	weekParam := c.Query("week")
 
-	var totalTeams int64
-	database.Model(&models.Team{}).Count(&totalTeams)
-	maxWeeks := 0
-	if totalTeams > 1 {
-		maxWeeks = (int(totalTeams) - 1) * 2
+	maxWeeks, err := s.maxWeeks(ctx)
+	if err != nil {
+		respondError(c, "Could not retrieve teams", err)
+		return
 	}
 
-	var matches []models.Match
-	query := database.
-		Preload("HomeTeam").
-		Preload("AwayTeam")
-
+	var filter db.MatchFilter
 	if weekParam != "" {
 		week, err := strconv.Atoi(weekParam)
 		if err != nil || week < 1 || (maxWeeks > 0 && week > maxWeeks) {
@@ -131,21 +73,21 @@ func GetMatches(c *gin.Context) {
 			if maxWeeks > 0 {
 				detail = "Week must be a number between 1 and " + strconv.Itoa(maxWeeks) + "."
 			}
-			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error:  "Invalid week parameter",
-				Detail: detail,
-			})
+			respondInvalid(c, "Invalid week parameter", detail)
 			return
 		}
-		query = query.Where("week = ?", week)
+		filter.Week = uint(week)
 	}
 
-	err := query.Order("week, id").Find(&matches).Error
+	matches, err := s.store.Matches().ListMatches(ctx, filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:  "Could not retrieve matches",
-			Detail: err.Error(),
-		})
+		respondError(c, "Could not retrieve matches", err)
+		return
+	}
+
+	teamNames, err := loadTeamNames(ctx, s.store.Teams())
+	if err != nil {
+		respondError(c, "Could not retrieve teams", err)
 		return
 	}
 
@@ -173,9 +115,9 @@ func GetMatches(c *gin.Context) {
 			ID:         match.ID,
 			Week:       match.Week,
 			HomeTeamID: match.HomeTeamID,
-			HomeTeam:   match.HomeTeam.Name,
+			HomeTeam:   teamNames[match.HomeTeamID],
 			AwayTeamID: match.AwayTeamID,
-			AwayTeam:   match.AwayTeam.Name,
+			AwayTeam:   teamNames[match.AwayTeamID],
 			HomeGoals:  homeGoals,
 			AwayGoals:  awayGoals,
 			PlayedAt:   playedAtStr,
@@ -190,75 +132,293 @@ func GetMatches(c *gin.Context) {
 	})
 }
 
+// GetMatchEvents returns the minute-by-minute timeline of a match
+// @Summary Get match events
+// @Description Returns the simulated timeline of a match (goals, cards, substitutions, half-time and full-time)
+// @Tags matches
+// @Produce json
+// @Param id path integer true "Match ID"
+// @Success 200 {object} MatchEventsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /matches/{id}/events [get]
+func (s *Server) GetMatchEvents(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	matchID, ok := parseIDParam(c, "Match")
+	if !ok {
+		return
+	}
+
+	match, err := s.store.Matches().FindMatch(ctx, matchID)
+	if err != nil {
+		respondError(c, "Could not retrieve match", err)
+		return
+	}
+	teamNames, err := loadTeamNames(ctx, s.store.Teams())
+	if err != nil {
+		respondError(c, "Could not retrieve match", err)
+		return
+	}
+
+	events, err := s.store.Matches().ListEvents(ctx, db.EventFilter{MatchID: match.ID})
+	if err != nil {
+		respondError(c, "Could not retrieve match events", err)
+		return
+	}
+
+	// Load the players referenced by the events (including since removed ones)
+	var playerIDs []uint
+	for _, event := range events {
+		if event.PlayerID != nil {
+			playerIDs = append(playerIDs, *event.PlayerID)
+		}
+		if event.AssistPlayerID != nil {
+			playerIDs = append(playerIDs, *event.AssistPlayerID)
+		}
+	}
+	playerNames := make(map[uint]string)
+	players, err := s.store.Teams().FindPlayers(ctx, playerIDs)
+	if err != nil {
+		respondError(c, "Could not retrieve match players", err)
+		return
+	}
+	for _, player := range players {
+		playerNames[player.ID] = player.Name
+	}
+
+	response := MatchEventsResponse{
+		MatchID:  match.ID,
+		Week:     match.Week,
+		HomeTeam: teamNames[match.HomeTeamID],
+		AwayTeam: teamNames[match.AwayTeamID],
+		Events:   []MatchEventResponse{},
+	}
+	if match.HomeGoals != nil && match.AwayGoals != nil {
+		hg, ag := int(*match.HomeGoals), int(*match.AwayGoals)
+		response.HomeGoals = &hg
+		response.AwayGoals = &ag
+	}
+
+	for _, event := range events {
+		eventResponse := MatchEventResponse{
+			Minute:    event.Minute,
+			Type:      event.Type,
+			TeamID:    event.TeamID,
+			PlayerID:  event.PlayerID,
+			AssistID:  event.AssistPlayerID,
+			HomeScore: event.HomeScore,
+			AwayScore: event.AwayScore,
+		}
+		if event.PlayerID != nil {
+			eventResponse.Player = playerNames[*event.PlayerID]
+		}
+		if event.AssistPlayerID != nil {
+			eventResponse.Assist = playerNames[*event.AssistPlayerID]
+		}
+		if event.TeamID != nil {
+			eventResponse.TeamName = teamNames[*event.TeamID]
+		}
+		if event.Type == models.EventHalfTime {
+			hg, ag := int(event.HomeScore), int(event.AwayScore)
+			response.HalfTimeHomeGoals = &hg
+			response.HalfTimeAwayGoals = &ag
+		}
+		response.Events = append(response.Events, eventResponse)
+	}
+	response.TotalEvents = len(response.Events)
+
+	c.JSON(http.StatusOK, response)
+}
+
 // PlayNextWeek simulates matches for the next week
 // @Summary Simulate next week's matches
-// @Description Simulates all matches for the next unplayed week using Poisson distribution
+// @Description Simulates all matches for the next unplayed week using Poisson distribution and returns the results with lambdas, the updated standings and, with predictions=true, refreshed championship predictions. The week is persisted in a single transaction; if any match fails the whole week is rolled back and the failing match IDs are returned.
 // @Tags simulation
 // @Produce json
-// @Success 200 {object} SimulationResponse
+// @Param Idempotency-Key header string false "Key that makes retries of this request replay the original response"
+// @Param predictions query boolean false "Also refresh championship predictions after the week"
+// @Success 200 {object} WeekSimulationResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /matches/next [post]
-func PlayNextWeek(c *gin.Context) {
-	sim := simulator.GetPoissonSimulator()
+func (s *Server) PlayNextWeek(c *gin.Context) {
+	withPredictions, ok := parsePredictionsParam(c)
+	if !ok {
+		return
+	}
 
-	// Assuming PlayNextWeek now returns (playedWeek uint, err error)
-	// If it only returns error, we need to adjust how to get the playedWeek for the message.
-	// For now, let's assume it only returns error and we simplify the message.
-	err := sim.PlayNextWeek() // If PlayNextWeek returns playedWeek, use: playedWeek, err := sim.PlayNextWeek()
+	sim := s.newSimulator(s.store, s.observers)
+	results, err := sim.PlayNextWeek(c.Request.Context())
 	if err != nil {
-		if err.Error() == "all matches have been played" || err.Error() == "no more weeks to play" {
-			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error:  "Simulation error",
-				Detail: err.Error(),
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error:  "Failed to simulate next week",
-				Detail: err.Error(),
-			})
+		respondError(c, "Failed to simulate next week", err)
+		return
+	}
+
+	s.respondWithWeekSimulation(c, "Next week successfully simulated", results, withPredictions)
+}
+
+// SimulateMatch simulates a single fixture
+// @Summary Simulate a single match
+// @Description Simulates one fixture by ID using Poisson distribution and returns the score with the lambdas used. Already played matches are only re-simulated when force=true.
+// @Tags simulation
+// @Produce json
+// @Param Idempotency-Key header string false "Key that makes retries of this request replay the original response"
+// @Param id path integer true "Match ID"
+// @Param force query boolean false "Re-simulate the match even if it has already been played"
+// @Success 200 {object} MatchSimulationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /matches/{id}/simulate [post]
+func (s *Server) SimulateMatch(c *gin.Context) {
+	matchID, ok := parseIDParam(c, "Match")
+	if !ok {
+		return
+	}
+
+	force := false
+	if forceParam := c.Query("force"); forceParam != "" {
+		parsed, err := strconv.ParseBool(forceParam)
+		if err != nil {
+			respondInvalid(c, "Invalid force parameter", "Force must be true or false.")
+			return
 		}
+		force = parsed
+	}
+
+	sim := s.newSimulator(s.store, s.observers)
+	result, err := sim.PlayMatch(c.Request.Context(), matchID, force)
+	if err != nil {
+		respondError(c, "Failed to simulate match", err)
+		return
+	}
+	buildResponse := func(ctx context.Context) (interface{}, error) {
+		teamNames, err := loadTeamNames(ctx, s.store.Teams())
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve teams: %w", err)
+		}
+		return toMatchSimulationResponse(*result, teamNames), nil
+	}
+	markCommitted(c, buildResponse)
+
+	response, err := buildResponse(c.Request.Context())
+	if err != nil {
+		respondError(c, "Could not build simulation response", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, SimulationResponse{
-		Message: "Next week successfully simulated", // Simplified message
-		Success: true,
+	c.JSON(http.StatusOK, response)
+}
+
+// PlayUntilWeek simulates weeks until the given week is complete
+// @Summary Play league up to a week
+// @Description Simulates all unplayed matches up to and including the given week in a single transaction and returns the results with the resulting standings
+// @Tags simulation
+// @Produce json
+// @Param Idempotency-Key header string false "Key that makes retries of this request replay the original response"
+// @Param week query integer true "Target week" minimum(1)
+// @Success 200 {object} PlayUntilResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /matches/play-until [post]
+func (s *Server) PlayUntilWeek(c *gin.Context) {
+	ctx := c.Request.Context()
+	weekParam := c.Query("week")
+
+	maxWeeks, err := s.maxWeeks(ctx)
+	if err != nil {
+		respondError(c, "Could not retrieve teams", err)
+		return
+	}
+
+	week, err := strconv.Atoi(weekParam)
+	if err != nil || week < 1 || (maxWeeks > 0 && week > maxWeeks) {
+		detail := "Week must be a positive number."
+		if maxWeeks > 0 {
+			detail = "Week must be a number between 1 and " + strconv.Itoa(maxWeeks) + "."
+		}
+		respondInvalid(c, "Invalid week parameter", detail)
+		return
+	}
+
+	sim := s.newSimulator(s.store, s.observers)
+	results, err := sim.PlayUntilWeek(ctx, uint(week))
+	if err != nil {
+		respondError(c, "Failed to play until week "+weekParam, err)
+		return
+	}
+	markCommitted(c, func(ctx context.Context) (interface{}, error) {
+		return s.buildPlayUntil(ctx, uint(week), results)
 	})
+
+	response, err := s.buildPlayUntil(ctx, uint(week), results)
+	if err != nil {
+		respondError(c, "Could not build simulation response", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// buildPlayUntil builds the response of playing the league until a week
+func (s *Server) buildPlayUntil(ctx context.Context, week uint, results []simModels.MatchResult) (PlayUntilResponse, error) {
+	standings, err := db.CalculateStandings(ctx, s.store)
+	if err != nil {
+		return PlayUntilResponse{}, fmt.Errorf("could not calculate standings: %w", err)
+	}
+
+	teamNames, err := loadTeamNames(ctx, s.store.Teams())
+	if err != nil {
+		return PlayUntilResponse{}, fmt.Errorf("could not retrieve teams: %w", err)
+	}
+
+	response := PlayUntilResponse{
+		Message:   fmt.Sprintf("League played until week %d", week),
+		Week:      week,
+		Results:   []MatchSimulationResponse{},
+		Standings: standings,
+	}
+	for _, result := range results {
+		response.Results = append(response.Results, toMatchSimulationResponse(result, teamNames))
+	}
+	return response, nil
 }
 
 // PlayAllWeeks simulates all remaining weeks
 // @Summary Simulate all remaining weeks
-// @Description Simulates all remaining unplayed matches until the end of the season
+// @Description Simulates all remaining unplayed matches until the end of the season and returns the weeks played, each result with lambdas and the final standings
 // @Tags simulation
 // @Produce json
-// @Success 200 {object} SimulationResponse
+// @Param Idempotency-Key header string false "Key that makes retries of this request replay the original response"
+// @Param predictions query boolean false "Also refresh championship predictions (omitted once the season is complete)"
+// @Success 200 {object} WeekSimulationResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /matches/all [post]
-func PlayAllWeeks(c *gin.Context) {
-	sim := simulator.GetPoissonSimulator()
+func (s *Server) PlayAllWeeks(c *gin.Context) {
+	withPredictions, ok := parsePredictionsParam(c)
+	if !ok {
+		return
+	}
 
-	err := sim.PlayAllRemainingWeeks()
+	sim := s.newSimulator(s.store, s.observers)
+	results, err := sim.PlayAllRemainingWeeks(c.Request.Context(), nil)
 	if err != nil {
-		if err.Error() == "all matches have been played" {
-			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error:  "Simulation error",
-				Detail: err.Error(),
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error:  "Failed to simulate all weeks",
-				Detail: err.Error(),
-			})
-		}
+		respondError(c, "Failed to simulate all weeks", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, SimulationResponse{
-		Message: "All remaining weeks successfully simulated",
-		Success: true,
-	})
+	s.respondWithWeekSimulation(c, "All remaining weeks successfully simulated", results, withPredictions)
 }
 
 // GetPredictions returns championship predictions
@@ -271,20 +431,19 @@ func PlayAllWeeks(c *gin.Context) {
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /predictions [get]
-func GetPredictions(c *gin.Context) {
+func (s *Server) GetPredictions(c *gin.Context) {
 	weekParam := c.Query("week")
 	if weekParam == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Week parameter is required"})
+		respondInvalid(c, "Week parameter is required", "")
 		return
 	}
 	weekInt, err := strconv.ParseUint(weekParam, 10, 32)
 
-	database := db.GetDB()
-	var totalTeams int64
-	database.Model(&models.Team{}).Count(&totalTeams)
-	maxWeeks := 0
-	if totalTeams > 1 {
-		maxWeeks = (int(totalTeams) - 1) * 2
+	ctx := c.Request.Context()
+	maxWeeks, teamsErr := s.maxWeeks(ctx)
+	if teamsErr != nil {
+		respondError(c, "Could not retrieve teams", teamsErr)
+		return
 	}
 	minPredictionWeek := uint64(1) // Allow predictions from week 1
 	if maxWeeks > 0 {
@@ -301,51 +460,248 @@ func GetPredictions(c *gin.Context) {
 		} else {
 			detail += "Please ensure teams are initialized for dynamic week calculation."
 		}
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:  "Invalid week parameter for predictions",
-			Detail: detail,
-		})
+		respondInvalid(c, "Invalid week parameter for predictions", detail)
 		return
 	}
 
 	week := uint(weekInt)
 
 	// Check existing predictions
-	var count int64
-	database.Model(&models.Prediction{}).Where("week = ?", week).Count(&count)
+	predictions, err := loadPredictions(ctx, s.store, week)
+	if err != nil {
+		respondError(c, "Could not retrieve predictions", err)
+		return
+	}
 	// If no predictions exist, generate new ones
-	if count == 0 {
-		predictor := simulator.GetMonteCarloPredictor(2000)        // Reduced from 10000 for faster predictions
-		_, err := predictor.PredictChampionshipProbabilities(week) // This will save predictions
+	generated := len(predictions) == 0
+	if generated {
+		predictor := s.newPredictor(s.store, predictionIterations, s.observers)
+		_, err := predictor.PredictChampionshipProbabilities(ctx, week, nil) // This will save predictions
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error:  "Failed to generate predictions",
-				Detail: err.Error(),
-			})
+			respondError(c, "Failed to generate predictions", err)
 			return
 		}
-	}
 
-	var predictions []PredictionResult
+		predictions, err = loadPredictions(ctx, s.store, week)
+		if err != nil {
+			respondError(c, "Could not retrieve predictions", err)
+			return
+		}
+	}
+	response := PredictionsListResponse{
+		Week:        week,
+		Predictions: predictions,
+		TotalTeams:  len(predictions),
+		Method:      predictionMethod,
+	}
+	if generated {
+		s.hub.Publish(stream.EventPredictions, response)
+	}
+	c.JSON(http.StatusOK, response)
+}
 
-	err = database.Table("predictions").
-		Select("predictions.team_id, teams.name as team_name, predictions.probability, TO_CHAR(predictions.created_at, 'YYYY-MM-DD HH24:MI:SS') as created_at").
-		Joins("JOIN teams ON predictions.team_id = teams.id").
-		Where("predictions.week = ?", week).
-		Order("predictions.probability DESC").
-		Scan(&predictions).Error
+// loadPredictions returns the saved predictions of a week, most likely champion first
+func loadPredictions(ctx context.Context, store db.Store, week uint) ([]PredictionResult, error) {
+	saved, err := store.Predictions().ListPredictions(ctx, week)
+	if err != nil {
+		return nil, err
+	}
 
+	teamNames, err := loadTeamNames(ctx, store.Teams())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:  "Could not retrieve predictions",
-			Detail: err.Error(),
+		return nil, err
+	}
+
+	predictions := make([]PredictionResult, 0, len(saved))
+	for _, prediction := range saved {
+		predictions = append(predictions, PredictionResult{
+			TeamID:      prediction.TeamID,
+			TeamName:    teamNames[prediction.TeamID],
+			Probability: prediction.Probability,
+			CreatedAt:   prediction.CreatedAt.Format("2006-01-02 15:04:05"),
 		})
+	}
+	return predictions, nil
+}
+
+// maxWeeks returns the number of weeks of a double round-robin season, or 0
+// if there are not enough teams to play one. An imported fixture with
+// postponed matches may last longer; its last week is returned then.
+func (s *Server) maxWeeks(ctx context.Context) (int, error) {
+	totalTeams, err := s.store.Teams().CountTeams(ctx)
+	if err != nil || totalTeams < 2 {
+		return 0, err
+	}
+	matches, err := s.store.Matches().ListMatches(ctx, db.MatchFilter{})
+	if err != nil {
+		return 0, err
+	}
+	weeks := (int(totalTeams) - 1) * 2
+	if len(matches) > 0 {
+		weeks = max(weeks, int(matches[len(matches)-1].Week))
+	}
+	return weeks, nil
+}
+
+// parseIDParam parses the ":id" path parameter as a positive ID
+// Writes a 400 response and returns false if the parameter is invalid
+func parseIDParam(c *gin.Context, resource string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		respondInvalid(c, "Invalid "+strings.ToLower(resource)+" ID", resource+" ID must be a positive number.")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// loadTeamNames returns the names of all teams keyed by team ID
+func loadTeamNames(ctx context.Context, repository db.TeamRepository) (map[uint]string, error) {
+	teams, err := repository.ListTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[uint]string, len(teams))
+	for _, team := range teams {
+		names[team.ID] = team.Name
+	}
+	return names, nil
+}
+
+// toMatchSimulationResponse converts a simulation result to its API representation
+func toMatchSimulationResponse(result simModels.MatchResult, teamNames map[uint]string) MatchSimulationResponse {
+	return MatchSimulationResponse{
+		MatchID:    result.MatchID,
+		Week:       result.Week,
+		HomeTeamID: result.HomeTeamID,
+		HomeTeam:   teamNames[result.HomeTeamID],
+		AwayTeamID: result.AwayTeamID,
+		AwayTeam:   teamNames[result.AwayTeamID],
+		HomeGoals:  result.HomeGoals,
+		AwayGoals:  result.AwayGoals,
+		HomeLambda: result.HomeLambda,
+		AwayLambda: result.AwayLambda,
+	}
+}
+
+// parsePredictionsParam parses the optional "predictions" query parameter
+// Writes a 400 response and returns false if the parameter is invalid
+func parsePredictionsParam(c *gin.Context) (bool, bool) {
+	param := c.Query("predictions")
+	if param == "" {
+		return false, true
+	}
+
+	withPredictions, err := strconv.ParseBool(param)
+	if err != nil {
+		respondInvalid(c, "Invalid predictions parameter", "Predictions must be true or false.")
+		return false, false
+	}
+	return withPredictions, true
+}
+
+// respondWithWeekSimulation writes the results of simulated weeks together with the
+// updated standings and, if requested, refreshed championship predictions
+func (s *Server) respondWithWeekSimulation(c *gin.Context, message string, results []simModels.MatchResult, withPredictions bool) {
+	// The weeks are committed; without predictions the response can be rebuilt
+	markCommitted(c, func(ctx context.Context) (interface{}, error) {
+		return s.buildWeekSimulation(ctx, message, results, false)
+	})
+
+	response, err := s.buildWeekSimulation(c.Request.Context(), message, results, withPredictions)
+	if err != nil {
+		respondError(c, "Could not build simulation response", err)
 		return
 	}
-	c.JSON(http.StatusOK, PredictionsListResponse{
+
+	c.JSON(http.StatusOK, response)
+}
+
+// buildWeekSimulation builds the response of simulated weeks with the updated
+// standings and, if requested, refreshed championship predictions
+func (s *Server) buildWeekSimulation(ctx context.Context, message string, results []simModels.MatchResult, withPredictions bool) (WeekSimulationResponse, error) {
+	standings, err := db.CalculateStandings(ctx, s.store)
+	if err != nil {
+		return WeekSimulationResponse{}, fmt.Errorf("could not calculate standings: %w", err)
+	}
+
+	teamNames, err := loadTeamNames(ctx, s.store.Teams())
+	if err != nil {
+		return WeekSimulationResponse{}, fmt.Errorf("could not retrieve teams: %w", err)
+	}
+
+	response := WeekSimulationResponse{
+		Message:     message,
+		Success:     true,
+		WeeksPlayed: []uint{},
+		Results:     []MatchSimulationResponse{},
+		Standings:   standings,
+	}
+	for _, result := range results {
+		if n := len(response.WeeksPlayed); n == 0 || response.WeeksPlayed[n-1] != result.Week {
+			response.WeeksPlayed = append(response.WeeksPlayed, result.Week)
+		}
+		response.Results = append(response.Results, toMatchSimulationResponse(result, teamNames))
+	}
+
+	if withPredictions && len(response.WeeksPlayed) > 0 {
+		predictions, err := s.refreshPredictions(ctx, response.WeeksPlayed[len(response.WeeksPlayed)-1], nil)
+		if err != nil {
+			return WeekSimulationResponse{}, fmt.Errorf("failed to generate predictions: %w", err)
+		}
+		response.Predictions = predictions
+	}
+
+	return response, nil
+}
+
+// refreshPredictions recalculates the championship predictions after the given week
+// Returns nil once every match has been played, as the champion is then decided.
+// The calculation stops when ctx is cancelled; progress may be nil.
+func (s *Server) refreshPredictions(ctx context.Context, week uint, progress base.ProgressFunc) (*PredictionsListResponse, error) {
+	remaining, err := s.store.Matches().CountMatches(ctx, db.MatchFilter{Played: db.Played(false)})
+	if err != nil {
+		return nil, err
+	}
+	if remaining == 0 {
+		return nil, nil
+	}
+
+	predictor := s.newPredictor(s.store, predictionIterations, s.observers)
+	probabilities, err := predictor.PredictChampionshipProbabilities(ctx, week, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	teamNames, err := loadTeamNames(ctx, s.store.Teams())
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt := time.Now().Format("2006-01-02 15:04:05")
+	predictions := make([]PredictionResult, 0, len(probabilities))
+	for teamID, probability := range probabilities {
+		predictions = append(predictions, PredictionResult{
+			TeamID:      teamID,
+			TeamName:    teamNames[teamID],
+			Probability: probability,
+			CreatedAt:   createdAt,
+		})
+	}
+	sort.Slice(predictions, func(i, j int) bool {
+		if predictions[i].Probability != predictions[j].Probability {
+			return predictions[i].Probability > predictions[j].Probability
+		}
+		return predictions[i].TeamID < predictions[j].TeamID
+	})
+
+	response := &PredictionsListResponse{
 		Week:        week,
 		Predictions: predictions,
 		TotalTeams:  len(predictions),
-		Method:      "Monte Carlo Simulation (2,000 iterations)",
-	})
+		Method:      predictionMethod,
+	}
+	s.hub.Publish(stream.EventPredictions, response)
+
+	return response, nil
 }
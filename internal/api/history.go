@@ -0,0 +1,153 @@
+// Package api - League rewind and snapshot history handler functions
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tarikbacak/insider-league-simulator/internal/db"
+)
+
+// RewindLeague clears all results after the given week
+// @Summary Rewind league to a week
+// @Description Clears the results of every week after the given one, together with their match events, injuries/suspensions and predictions. A snapshot is taken first so the rewind can be undone. Week 0 rewinds to the start of the season.
+// @Tags history
+// @Produce json
+// @Param week query integer true "Last week to keep" minimum(0)
+// @Success 200 {object} RewindResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /matches/rewind [post]
+func (s *Server) RewindLeague(c *gin.Context) {
+	weekParam := c.Query("week")
+
+	maxWeeks, err := s.maxWeeks(c.Request.Context())
+	if err != nil {
+		respondError(c, "Could not retrieve teams", err)
+		return
+	}
+
+	week, err := strconv.Atoi(weekParam)
+	if err != nil || week < 0 || (maxWeeks > 0 && week > maxWeeks) {
+		detail := "Week must be zero or a positive number."
+		if maxWeeks > 0 {
+			detail = "Week must be a number between 0 and " + strconv.Itoa(maxWeeks) + "."
+		}
+		respondInvalid(c, "Invalid week parameter", detail)
+		return
+	}
+
+	snapshot, err := db.RewindToWeek(c.Request.Context(), s.store, uint(week))
+	if err != nil {
+		respondError(c, "Failed to rewind league to week "+weekParam, err)
+		return
+	}
+
+	s.publishLeagueReset("rewind")
+
+	standings, err := db.CalculateStandings(c.Request.Context(), s.store)
+	if err != nil {
+		respondError(c, "Could not calculate standings", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, RewindResponse{
+		Message:   "League rewound to week " + weekParam,
+		Week:      uint(week),
+		Snapshot:  *snapshot,
+		Standings: standings,
+	})
+}
+
+// GetSnapshots returns the league snapshot history
+// @Summary List league snapshots
+// @Description Returns the history of saved league snapshots, newest first
+// @Tags history
+// @Produce json
+// @Success 200 {object} SnapshotsResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /snapshots [get]
+func (s *Server) GetSnapshots(c *gin.Context) {
+	snapshots, err := db.ListSnapshots(c.Request.Context(), s.store)
+	if err != nil {
+		respondError(c, "Could not retrieve snapshots", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SnapshotsResponse{
+		Snapshots:      snapshots,
+		TotalSnapshots: len(snapshots),
+	})
+}
+
+// CreateSnapshot saves the current league progress
+// @Summary Create league snapshot
+// @Description Saves the current results, match events, injuries/suspensions and predictions so the league can be restored to this point later
+// @Tags history
+// @Accept json
+// @Produce json
+// @Param snapshot body SnapshotRequest false "Snapshot label"
+// @Success 201 {object} models.LeagueSnapshot
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /snapshots [post]
+func (s *Server) CreateSnapshot(c *gin.Context) {
+	var request SnapshotRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			respondInvalid(c, "Invalid snapshot data", err.Error())
+			return
+		}
+	}
+	if request.Label == "" {
+		request.Label = "Manual snapshot"
+	}
+
+	snapshot, err := db.CreateSnapshot(c.Request.Context(), s.store, request.Label)
+	if err != nil {
+		respondError(c, "Could not create snapshot", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, snapshot)
+}
+
+// RestoreSnapshot restores the league to a saved snapshot
+// @Summary Restore league snapshot
+// @Description Replaces the current league progress with the state saved in a snapshot. The current state is saved as a new snapshot first so the restore can be undone.
+// @Tags history
+// @Produce json
+// @Param id path integer true "Snapshot ID"
+// @Success 200 {object} RestoreResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /snapshots/{id}/restore [post]
+func (s *Server) RestoreSnapshot(c *gin.Context) {
+	snapshotID, ok := parseIDParam(c, "Snapshot")
+	if !ok {
+		return
+	}
+
+	snapshot, err := db.RestoreSnapshot(c.Request.Context(), s.store, snapshotID)
+	if err != nil {
+		respondError(c, "Failed to restore snapshot", err)
+		return
+	}
+
+	s.publishLeagueReset("restore")
+
+	standings, err := db.CalculateStandings(c.Request.Context(), s.store)
+	if err != nil {
+		respondError(c, "Could not calculate standings", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, RestoreResponse{
+		Message:   "Snapshot " + c.Param("id") + " restored",
+		Snapshot:  *snapshot,
+		Standings: standings,
+	})
+}
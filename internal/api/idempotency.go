@@ -0,0 +1,201 @@
+// Package api - Idempotency key middleware for simulation endpoints
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client's idempotency key
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from a stored idempotency key
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// idempotencyKeyTTL is how long a stored response is replayed for a key
+	idempotencyKeyTTL = 24 * time.Hour
+	// maxIdempotencyKeyLength is the longest key accepted
+	maxIdempotencyKeyLength = 255
+	// committedOutcomeTimeout limits rebuilding the response of a committed request
+	committedOutcomeTimeout = 30 * time.Second
+	// idempotencyClaimTimeout is how long a key stays claimed by a request that
+	// never finished (the process crashed) when requests have no timeout
+	idempotencyClaimTimeout = 10 * time.Minute
+
+	// committedOutcomeKey is the gin context key of the committedOutcome of a request
+	committedOutcomeKey = "idempotency.committed_outcome"
+)
+
+// committedOutcome rebuilds the response of a request whose changes are committed
+type committedOutcome func(ctx context.Context) (interface{}, error)
+
+// markCommitted records that the changes of a request are committed
+// If the handler then fails to build its response, for example because the
+// request timed out, the idempotency middleware keeps the key and stores the
+// response built by outcome instead, so a retry does not repeat the changes.
+func markCommitted(c *gin.Context, outcome committedOutcome) {
+	c.Set(committedOutcomeKey, outcome)
+}
+
+// responseRecorder captures the response body while writing it to the client
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write writes the data to the client and keeps a copy of it
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+// WriteString writes the string to the client and keeps a copy of it
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// idempotencyMiddleware makes state-changing endpoints safe to retry
+// When a request carries an Idempotency-Key header, its response is stored and
+// returned again for later requests with the same key instead of re-running the
+// handler. Requests without the header are processed normally.
+func (s *Server) idempotencyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			respondInvalid(c, "Invalid idempotency key", "Idempotency-Key must be at most 255 characters.")
+			return
+		}
+
+		// Not bound to the request context: the outcome must be stored even if
+		// the handler ran into the request timeout
+		database := s.db
+
+		// Forget expired keys so they can be reused, and release claims whose
+		// request can no longer be running, e.g. because the process crashed
+		now := time.Now()
+		if err := database.Where("created_at < ? OR (status_code = 0 AND created_at < ?)",
+			now.Add(-idempotencyKeyTTL), now.Add(-s.idempotencyClaimTimeout())).
+			Delete(&models.IdempotencyKey{}).Error; err != nil {
+			respondError(c, "Could not process idempotency key", err)
+			return
+		}
+
+		// Claim the key; only one request can insert it
+		record := models.IdempotencyKey{
+			Key:    key,
+			Method: c.Request.Method,
+			Path:   c.Request.URL.RequestURI(),
+		}
+		result := database.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+		if result.Error != nil {
+			respondError(c, "Could not process idempotency key", result.Error)
+			return
+		}
+		if result.RowsAffected == 0 {
+			s.replayIdempotentResponse(c, key)
+			return
+		}
+
+		// A panicking handler must not leave the key claimed until the claim
+		// times out; the panic is passed on to the recovery middleware
+		defer func() {
+			if r := recover(); r != nil {
+				body, _ := json.Marshal(ErrorResponse{Error: "Internal server error", Code: CodeInternalError})
+				s.finishIdempotencyKey(c, key, http.StatusInternalServerError, string(body))
+				panic(r)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		s.finishIdempotencyKey(c, key, recorder.Status(), recorder.body.String())
+	}
+}
+
+// idempotencyClaimTimeout returns how long a claimed key counts as in progress
+// The response of a request is stored at the latest when its timeout expired
+// and its committed outcome was rebuilt.
+func (s *Server) idempotencyClaimTimeout() time.Duration {
+	if timeout := s.config.Server.RequestTimeout; timeout > 0 {
+		return timeout + committedOutcomeTimeout
+	}
+	return idempotencyClaimTimeout
+}
+
+// finishIdempotencyKey stores the response of the request that claimed key
+// An error response releases the key unless the request's changes are committed.
+func (s *Server) finishIdempotencyKey(c *gin.Context, key string, status int, response string) {
+	database := s.db
+	if status >= http.StatusInternalServerError {
+		outcome, committed := c.Get(committedOutcomeKey)
+		if !committed {
+			// Nothing was changed, so the client can retry the request
+			database.Delete(&models.IdempotencyKey{}, "key = ?", key)
+			return
+		}
+		// The changes are committed; retries get the committed outcome, or
+		// this error if even that cannot be built, but never repeat them
+		if body, err := rebuildOutcome(outcome.(committedOutcome)); err == nil {
+			status, response = http.StatusOK, body
+		}
+	}
+	database.Model(&models.IdempotencyKey{}).Where("key = ?", key).Updates(map[string]interface{}{
+		"status_code": status,
+		"response":    response,
+	})
+}
+
+// rebuildOutcome builds the JSON response of a committed request
+// The request context may already be expired, so a new one is used.
+func rebuildOutcome(outcome committedOutcome) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), committedOutcomeTimeout)
+	defer cancel()
+
+	response, err := outcome(ctx)
+	if err != nil {
+		return "", err
+	}
+	body, err := json.Marshal(response)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// replayIdempotentResponse answers a request whose idempotency key was already used
+func (s *Server) replayIdempotentResponse(c *gin.Context, key string) {
+	var existing models.IdempotencyKey
+	if err := s.db.First(&existing, "key = ?", key).Error; err != nil {
+		respondError(c, "Could not process idempotency key", err)
+		return
+	}
+
+	if existing.Method != c.Request.Method || existing.Path != c.Request.URL.RequestURI() {
+		writeError(c, http.StatusUnprocessableEntity, CodeIdempotencyKeyReused, "Idempotency key reused",
+			"The key was already used for "+existing.Method+" "+existing.Path+".")
+		return
+	}
+	if existing.StatusCode == 0 {
+		writeError(c, http.StatusConflict, CodeRequestInProgress, "Request in progress",
+			"A request with the same idempotency key is still being processed.")
+		return
+	}
+
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(existing.StatusCode, "application/json; charset=utf-8", []byte(existing.Response))
+	c.Abort()
+}
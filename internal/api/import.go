@@ -0,0 +1,89 @@
+// Package api - Historical results import handler
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tarikbacak/insider-league-simulator/internal/db"
+	"github.com/tarikbacak/insider-league-simulator/internal/importer"
+)
+
+// maxImportSize is the largest file accepted by the import and archive restore endpoints
+const maxImportSize = 10 << 20
+
+// ImportLeague replaces the league with the fixtures and results of a CSV file
+// @Summary Import historical results
+// @Description Replaces the league with the teams, fixtures and results of a CSV file in the football-data.co.uk layout (Date, HomeTeam, AwayTeam, FTHG, FTAG; or Date, Home, Away, HG, AG). The file is sent as the "file" field of a multipart form or as the request body. Matches without a score are imported as unplayed fixtures, weeks are numbered in date order and team strengths are fitted from the results. Rows that could not be mapped are listed in skipped_rows. Like /init, the current league and its snapshots are deleted.
+// @Tags import
+// @Accept multipart/form-data,text/csv
+// @Produce json
+// @Param file formData file false "football-data CSV file"
+// @Success 201 {object} ImportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /import [post]
+func (s *Server) ImportLeague(c *gin.Context) {
+	file, err := importFile(c)
+	if err != nil {
+		respondImportReadError(c, err)
+		return
+	}
+	defer file.Close()
+
+	season, err := importer.Parse(file)
+	if err != nil {
+		respondImportReadError(c, err)
+		return
+	}
+
+	var result *importer.Result
+	err = db.ResetLeague(c.Request.Context(), s.store, func(ctx context.Context, store db.Store) error {
+		result, err = importer.Load(ctx, store, season)
+		return err
+	})
+	if err != nil {
+		respondError(c, "Could not import league", err)
+		return
+	}
+
+	s.publishLeagueReset("import")
+
+	c.JSON(http.StatusCreated, ImportResponse{
+		Message:  "League imported successfully",
+		Division: season.Division,
+		Season:   season.Name,
+		Result:   *result,
+	})
+}
+
+// importFile returns the uploaded file of an import: the "file" field of a
+// multipart form, or else the request body. Reads fail after maxImportSize bytes.
+func importFile(c *gin.Context) (io.ReadCloser, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	if c.ContentType() != "multipart/form-data" {
+		return c.Request.Body, nil
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+	return header.Open()
+}
+
+// respondImportReadError writes the response for a file that could not be read
+func respondImportReadError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		writeError(c, http.StatusRequestEntityTooLarge, CodeFileTooLarge, "Import file is too large", "The file must not be larger than 10 MB.")
+	case errors.Is(err, http.ErrMissingFile):
+		respondInvalid(c, "Import file is missing", "Send the file as the \"file\" field of a multipart form or as the request body.")
+	default:
+		respondError(c, "Could not read import file", err)
+	}
+}
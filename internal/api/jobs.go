@@ -0,0 +1,265 @@
+// Package api - Background job handler functions
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tarikbacak/insider-league-simulator/internal/db"
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/base"
+)
+
+// Job types accepted by POST /jobs
+const (
+	JobTypePredictions = "predictions" // Recalculates the championship predictions of a week
+	JobTypePlayAll     = "play_all"    // Simulates every remaining week
+)
+
+// CreateJob submits a background job
+// @Summary Submit background job
+// @Description Queues a long-running operation and returns immediately with the job ID. "predictions" recalculates the championship predictions of a week (params: {"week": n}, 0 or omitted for the last completed week); "play_all" simulates every remaining week (params: {"predictions": true} to refresh predictions afterwards). Poll GET /jobs/{id} for progress and the result.
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Param job body JobRequest true "Job type and parameters"
+// @Success 202 {object} JobResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /jobs [post]
+func (s *Server) CreateJob(c *gin.Context) {
+	var request JobRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalid(c, "Invalid job data", err.Error())
+		return
+	}
+	if detail := s.validateJobParams(c.Request.Context(), request.Type, request.Params); detail != "" {
+		respondInvalid(c, "Invalid job parameters", detail)
+		return
+	}
+
+	job, err := s.jobs.Submit(request.Type, request.Params)
+	if err != nil {
+		respondError(c, "Could not submit job", err)
+		return
+	}
+
+	c.Header("Location", "/api/v1/jobs/"+strconv.FormatUint(uint64(job.ID), 10))
+	c.JSON(http.StatusAccepted, toJobResponse(job))
+}
+
+// GetJobs returns the most recent background jobs
+// @Summary List background jobs
+// @Description Returns background jobs, newest first
+// @Tags jobs
+// @Produce json
+// @Param status query string false "Job status" Enums(queued, running, succeeded, failed, cancelled)
+// @Param limit query integer false "Maximum number of jobs to return (1-100, default 20)"
+// @Success 200 {object} JobsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /jobs [get]
+func (s *Server) GetJobs(c *gin.Context) {
+	limit := 20
+	if limitParam := c.Query("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed < 1 || parsed > 100 {
+			respondInvalid(c, "Invalid limit parameter", "Limit must be a number between 1 and 100.")
+			return
+		}
+		limit = parsed
+	}
+
+	status := c.Query("status")
+	switch status {
+	case "", models.JobQueued, models.JobRunning, models.JobSucceeded, models.JobFailed, models.JobCancelled:
+	default:
+		respondInvalid(c, "Invalid status parameter", "Status must be queued, running, succeeded, failed or cancelled.")
+		return
+	}
+
+	list, err := s.jobs.List(c.Request.Context(), status, limit)
+	if err != nil {
+		respondError(c, "Could not retrieve jobs", err)
+		return
+	}
+
+	responses := make([]JobResponse, 0, len(list))
+	for _, job := range list {
+		response := toJobResponse(job)
+		response.Result = nil // Results are only returned by GET /jobs/{id}
+		responses = append(responses, response)
+	}
+
+	c.JSON(http.StatusOK, JobsResponse{
+		Jobs:      responses,
+		TotalJobs: len(responses),
+	})
+}
+
+// GetJob returns the status, progress and result of a background job
+// @Summary Get background job
+// @Description Returns the status and progress (0-100) of a job; once it has succeeded the result holds the same response the synchronous endpoint returns
+// @Tags jobs
+// @Produce json
+// @Param id path integer true "Job ID"
+// @Success 200 {object} JobResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /jobs/{id} [get]
+func (s *Server) GetJob(c *gin.Context) {
+	jobID, ok := parseIDParam(c, "Job")
+	if !ok {
+		return
+	}
+
+	job, err := s.jobs.Get(c.Request.Context(), jobID)
+	if err != nil {
+		respondError(c, "Could not retrieve job", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toJobResponse(job))
+}
+
+// CancelJob cancels a background job
+// @Summary Cancel background job
+// @Description Cancels a queued job immediately. A running job is stopped at its next checkpoint (between Monte Carlo iteration batches or simulated weeks) and then reported as cancelled; weeks already simulated by a play_all job are kept and listed in its result.
+// @Tags jobs
+// @Produce json
+// @Param id path integer true "Job ID"
+// @Success 200 {object} JobResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /jobs/{id}/cancel [post]
+func (s *Server) CancelJob(c *gin.Context) {
+	jobID, ok := parseIDParam(c, "Job")
+	if !ok {
+		return
+	}
+
+	job, err := s.jobs.Cancel(jobID)
+	if err != nil {
+		respondError(c, "Could not cancel job", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toJobResponse(job))
+}
+
+// validateJobParams checks the parameters of a job type before it is queued
+// Returns a description of the problem, or an empty string if they are valid.
+// Unknown job types are reported by the queue.
+func (s *Server) validateJobParams(ctx context.Context, jobType string, params json.RawMessage) string {
+	switch jobType {
+	case JobTypePredictions:
+		var p PredictionsJobParams
+		if err := decodeJobParams(params, &p); err != nil {
+			return err.Error()
+		}
+		if maxWeeks, _ := s.maxWeeks(ctx); maxWeeks > 0 && int(p.Week) > maxWeeks {
+			return "Week must be a number between 0 and " + strconv.Itoa(maxWeeks) + "."
+		}
+	case JobTypePlayAll:
+		var p PlayAllJobParams
+		if err := decodeJobParams(params, &p); err != nil {
+			return err.Error()
+		}
+	}
+	return ""
+}
+
+// decodeJobParams decodes job parameters, rejecting unknown fields
+// Missing parameters decode to the zero value.
+func decodeJobParams(params json.RawMessage, target interface{}) error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(params))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(target)
+}
+
+// runPredictionsJob recalculates the championship predictions of a week
+func (s *Server) runPredictionsJob(ctx context.Context, params json.RawMessage, progress base.ProgressFunc) (interface{}, error) {
+	var p PredictionsJobParams
+	if err := decodeJobParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	week := p.Week
+	if week == 0 {
+		lastWeek, err := db.LastCompletedWeek(ctx, s.store)
+		if err != nil {
+			return nil, err
+		}
+		week = lastWeek
+	}
+
+	predictions, err := s.refreshPredictions(ctx, week, progress)
+	if err != nil {
+		return nil, err
+	}
+	if predictions == nil {
+		return nil, base.ErrSeasonComplete
+	}
+	return predictions, nil
+}
+
+// runPlayAllJob simulates every remaining week
+func (s *Server) runPlayAllJob(ctx context.Context, params json.RawMessage, progress base.ProgressFunc) (interface{}, error) {
+	var p PlayAllJobParams
+	if err := decodeJobParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	sim := s.newSimulator(s.store, s.observers)
+	results, err := sim.PlayAllRemainingWeeks(ctx, progress)
+	if err != nil {
+		if len(results) == 0 {
+			return nil, err
+		}
+		// The weeks played before the job stopped are kept; report them with
+		// the error. ctx may be cancelled, so they are read without it.
+		partial, buildErr := s.buildWeekSimulation(context.WithoutCancel(ctx), "", results, false)
+		if buildErr != nil {
+			return nil, err
+		}
+		partial.Message = fmt.Sprintf("Simulation stopped after %d weeks", len(partial.WeeksPlayed))
+		partial.Success = false
+		return partial, err
+	}
+
+	return s.buildWeekSimulation(ctx, "All remaining weeks successfully simulated", results, p.Predictions)
+}
+
+// toJobResponse converts a job to its API representation
+func toJobResponse(job models.Job) JobResponse {
+	response := JobResponse{
+		ID:         job.ID,
+		Type:       job.Type,
+		Status:     job.Status,
+		Progress:   job.Progress,
+		Params:     json.RawMessage(job.Params),
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+	}
+	if job.Params == "" {
+		response.Params = json.RawMessage("{}")
+	}
+	if job.Result != "" {
+		response.Result = json.RawMessage(job.Result)
+	}
+	return response
+}
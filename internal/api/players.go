@@ -0,0 +1,333 @@
+// Package api - Squad and player statistics handler functions
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tarikbacak/insider-league-simulator/internal/db"
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/squad"
+)
+
+// GetSquad returns the squad of a team
+// @Summary Get team squad
+// @Description Returns all players in a team's squad with their injury/suspension status and its effect on team strength
+// @Tags players
+// @Produce json
+// @Param id path integer true "Team ID"
+// @Success 200 {object} SquadResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /teams/{id}/players [get]
+func (s *Server) GetSquad(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	teamID, ok := parseIDParam(c, "Team")
+	if !ok {
+		return
+	}
+
+	team, err := s.store.Teams().FindTeam(ctx, teamID)
+	if err != nil {
+		respondError(c, "Could not retrieve squad", err)
+		return
+	}
+	squadPlayers, err := s.store.Teams().ListPlayers(ctx, team.ID)
+	if err != nil {
+		respondError(c, "Could not retrieve squad", err)
+		return
+	}
+
+	unavailable, err := s.store.Teams().UnavailablePlayers(ctx, team.ID)
+	if err != nil {
+		respondError(c, "Could not retrieve squad availability", err)
+		return
+	}
+
+	players := make([]PlayerResponse, 0, len(squadPlayers))
+	for _, player := range squadPlayers {
+		players = append(players, toPlayerResponse(player, unavailable[player.ID]))
+	}
+	factors := squad.StrengthFactors(squadPlayers, squad.Unavailable(unavailable, 0))
+
+	c.JSON(http.StatusOK, SquadResponse{
+		TeamID:        team.ID,
+		TeamName:      team.Name,
+		Players:       players,
+		TotalPlayers:  len(players),
+		AttackFactor:  factors.Attack,
+		DefenseFactor: factors.Defense,
+	})
+}
+
+// CreatePlayer adds a new player to a team's squad
+// @Summary Add player to squad
+// @Description Adds a new player to the squad of the specified team
+// @Tags players
+// @Accept json
+// @Produce json
+// @Param id path integer true "Team ID"
+// @Param player body PlayerRequest true "Player details"
+// @Success 201 {object} PlayerResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /teams/{id}/players [post]
+func (s *Server) CreatePlayer(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	teamID, ok := parseIDParam(c, "Team")
+	if !ok {
+		return
+	}
+
+	var request PlayerRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalid(c, "Invalid player data", err.Error())
+		return
+	}
+
+	team, err := s.store.Teams().FindTeam(ctx, teamID)
+	if err != nil {
+		respondError(c, "Could not retrieve team", err)
+		return
+	}
+
+	players := []models.Player{{TeamID: team.ID}}
+	applyPlayerRequest(&players[0], request)
+	if err := s.store.Teams().CreatePlayers(ctx, players); err != nil {
+		respondError(c, "Could not create player", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toPlayerResponse(players[0], 0))
+}
+
+// GetPlayer returns a single player
+// @Summary Get player
+// @Description Returns the details of a single player
+// @Tags players
+// @Produce json
+// @Param id path integer true "Player ID"
+// @Success 200 {object} PlayerResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /players/{id} [get]
+func (s *Server) GetPlayer(c *gin.Context) {
+	player, ok := s.findPlayer(c)
+	if !ok {
+		return
+	}
+
+	s.respondWithPlayer(c, player)
+}
+
+// UpdatePlayer updates an existing player
+// @Summary Update player
+// @Description Updates the details of an existing player
+// @Tags players
+// @Accept json
+// @Produce json
+// @Param id path integer true "Player ID"
+// @Param player body PlayerRequest true "Player details"
+// @Success 200 {object} PlayerResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /players/{id} [put]
+func (s *Server) UpdatePlayer(c *gin.Context) {
+	var request PlayerRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalid(c, "Invalid player data", err.Error())
+		return
+	}
+
+	player, ok := s.findPlayer(c)
+	if !ok {
+		return
+	}
+
+	applyPlayerRequest(&player, request)
+	if err := s.store.Teams().SavePlayer(c.Request.Context(), &player); err != nil {
+		respondError(c, "Could not update player", err)
+		return
+	}
+
+	s.respondWithPlayer(c, player)
+}
+
+// DeletePlayer removes a player from their squad
+// @Summary Delete player
+// @Description Removes a player from their team's squad (goals already scored are kept in statistics)
+// @Tags players
+// @Produce json
+// @Param id path integer true "Player ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /players/{id} [delete]
+func (s *Server) DeletePlayer(c *gin.Context) {
+	player, ok := s.findPlayer(c)
+	if !ok {
+		return
+	}
+
+	if err := s.store.Teams().DeletePlayer(c.Request.Context(), player.ID); err != nil {
+		respondError(c, "Could not delete player", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetTopScorers returns the league's top scorers table
+// @Summary Get top scorers
+// @Description Returns players ordered by goals scored (then assists) in simulated matches
+// @Tags players
+// @Produce json
+// @Param limit query integer false "Maximum number of players to return (1-100, default 20)"
+// @Success 200 {object} TopScorersResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /stats/scorers [get]
+func (s *Server) GetTopScorers(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	limit := 20
+	if limitParam := c.Query("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed < 1 || parsed > 100 {
+			respondInvalid(c, "Invalid limit parameter", "Limit must be a number between 1 and 100.")
+			return
+		}
+		limit = parsed
+	}
+
+	goals, err := s.store.Matches().ListEvents(ctx, db.EventFilter{Type: models.EventGoal})
+	if err != nil {
+		respondError(c, "Could not calculate top scorers", err)
+		return
+	}
+
+	// Count goals and assists per player; only players who scored are listed
+	tallies := make(map[uint]*ScorerResult)
+	tally := func(playerID uint) *ScorerResult {
+		if tallies[playerID] == nil {
+			tallies[playerID] = &ScorerResult{PlayerID: playerID}
+		}
+		return tallies[playerID]
+	}
+	for _, goal := range goals {
+		if goal.PlayerID != nil {
+			tally(*goal.PlayerID).Goals++
+		}
+		if goal.AssistPlayerID != nil {
+			tally(*goal.AssistPlayerID).Assists++
+		}
+	}
+	var scorerIDs []uint
+	for playerID, result := range tallies {
+		if result.Goals > 0 {
+			scorerIDs = append(scorerIDs, playerID)
+		}
+	}
+
+	// Players who have since been removed keep their goals
+	players, err := s.store.Teams().FindPlayers(ctx, scorerIDs)
+	if err != nil {
+		respondError(c, "Could not calculate top scorers", err)
+		return
+	}
+	teamNames, err := loadTeamNames(ctx, s.store.Teams())
+	if err != nil {
+		respondError(c, "Could not calculate top scorers", err)
+		return
+	}
+
+	scorers := []ScorerResult{}
+	for _, player := range players {
+		teamName, ok := teamNames[player.TeamID]
+		if !ok {
+			continue
+		}
+		result := tallies[player.ID]
+		result.PlayerName, result.TeamID, result.TeamName = player.Name, player.TeamID, teamName
+		scorers = append(scorers, *result)
+	}
+	sort.Slice(scorers, func(i, j int) bool {
+		if scorers[i].Goals != scorers[j].Goals {
+			return scorers[i].Goals > scorers[j].Goals
+		}
+		if scorers[i].Assists != scorers[j].Assists {
+			return scorers[i].Assists > scorers[j].Assists
+		}
+		return scorers[i].PlayerName < scorers[j].PlayerName
+	})
+	if len(scorers) > limit {
+		scorers = scorers[:limit]
+	}
+
+	c.JSON(http.StatusOK, TopScorersResponse{
+		Scorers:      scorers,
+		TotalScorers: len(scorers),
+	})
+}
+
+// findPlayer loads the player identified by the ":id" path parameter
+// Writes the error response and returns false if the player cannot be loaded
+func (s *Server) findPlayer(c *gin.Context) (models.Player, bool) {
+	playerID, ok := parseIDParam(c, "Player")
+	if !ok {
+		return models.Player{}, false
+	}
+
+	player, err := s.store.Teams().FindPlayer(c.Request.Context(), playerID)
+	if err != nil {
+		respondError(c, "Could not retrieve player", err)
+		return player, false
+	}
+
+	return player, true
+}
+
+// respondWithPlayer writes a player together with their current availability
+func (s *Server) respondWithPlayer(c *gin.Context, player models.Player) {
+	unavailable, err := s.store.Teams().UnavailablePlayers(c.Request.Context(), player.TeamID)
+	if err != nil {
+		respondError(c, "Could not retrieve player availability", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toPlayerResponse(player, unavailable[player.ID]))
+}
+
+// applyPlayerRequest copies the editable fields of a request onto a player
+func applyPlayerRequest(player *models.Player, request PlayerRequest) {
+	player.Name = request.Name
+	player.ShirtNumber = request.ShirtNumber
+	player.Position = request.Position
+	player.Rating = request.Rating
+	player.ScoringShare = request.ScoringShare
+}
+
+// toPlayerResponse converts a player model to its API representation
+// matchesOut is the number of team matches the player still misses through injury or suspension
+func toPlayerResponse(player models.Player, matchesOut uint) PlayerResponse {
+	return PlayerResponse{
+		ID:           player.ID,
+		TeamID:       player.TeamID,
+		Name:         player.Name,
+		ShirtNumber:  player.ShirtNumber,
+		Position:     player.Position,
+		Rating:       player.Rating,
+		ScoringShare: player.ScoringShare,
+		Available:    matchesOut == 0,
+		MatchesOut:   matchesOut,
+	}
+}
@@ -1,12 +1,22 @@
 package api
 
-import "github.com/tarikbacak/insider-league-simulator/internal/models"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/archive"
+	"github.com/tarikbacak/insider-league-simulator/internal/importer"
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+)
 
 // ErrorResponse represents a generic error response.
 type ErrorResponse struct {
 	Error     string `json:"error" example:"Error message"`
+	Code      string `json:"code" example:"match_not_found"` // Machine-readable error code
 	Detail    string `json:"detail,omitempty" example:"Detailed error information"`
 	Timestamp string `json:"timestamp,omitempty" example:"2023-10-27 10:00:00"`
+	// FailedMatchIDs lists the matches that could not be simulated when a week was rolled back
+	FailedMatchIDs []uint `json:"failed_match_ids,omitempty" example:"3,4"`
 }
 
 // APIInfoResponse defines the structure for the homepage API information.
@@ -45,6 +55,21 @@ type InitResponse struct {
 	Timestamp string `json:"timestamp" example:"2023-10-27 10:00:00"`
 }
 
+// ImportResponse summarizes an imported league
+type ImportResponse struct {
+	Message  string `json:"message" example:"League imported successfully"`
+	Division string `json:"division,omitempty" example:"E0"`      // Div column of the file, if present
+	Season   string `json:"season,omitempty" example:"2023/2024"` // Season column of the file, if present
+	importer.Result
+}
+
+// ArchiveRestoreResponse summarizes a restored league archive
+type ArchiveRestoreResponse struct {
+	Message string `json:"message" example:"League archive restored"`
+	archive.Summary
+	Standings []models.Standing `json:"standings"`
+}
+
 // StandingsResponse wraps the list of standings and total count.
 type StandingsResponse struct {
 	Standings  []models.Standing `json:"standings"`
@@ -72,10 +97,112 @@ type MatchesResponse struct {
 	Week         string                `json:"week,omitempty" example:"3"`
 }
 
-// SimulationResponse is a generic response for simulation actions.
-type SimulationResponse struct {
-	Message string `json:"message" example:"Operation successful"`
-	Success bool   `json:"success" example:"true"`
+// MatchEventResponse defines the structure for a single entry in a match timeline.
+type MatchEventResponse struct {
+	Minute    uint   `json:"minute" example:"23"`
+	Type      string `json:"type" example:"goal"`
+	TeamID    *uint  `json:"team_id,omitempty" example:"1"`
+	TeamName  string `json:"team_name,omitempty" example:"Team A"`
+	PlayerID  *uint  `json:"player_id,omitempty" example:"9"`
+	Player    string `json:"player_name,omitempty" example:"Mauro Icardi"`
+	AssistID  *uint  `json:"assist_player_id,omitempty" example:"10"`
+	Assist    string `json:"assist_player_name,omitempty" example:"Dries Mertens"`
+	HomeScore uint   `json:"home_score" example:"1"`
+	AwayScore uint   `json:"away_score" example:"0"`
+}
+
+// MatchEventsResponse wraps a match's timeline together with its half-time and final score.
+type MatchEventsResponse struct {
+	MatchID           uint                 `json:"match_id" example:"1"`
+	Week              uint                 `json:"week" example:"1"`
+	HomeTeam          string               `json:"home_team_name" example:"Team A"`
+	AwayTeam          string               `json:"away_team_name" example:"Team B"`
+	HomeGoals         *int                 `json:"home_goals,omitempty" example:"2"`
+	AwayGoals         *int                 `json:"away_goals,omitempty" example:"1"`
+	HalfTimeHomeGoals *int                 `json:"half_time_home_goals,omitempty" example:"1"`
+	HalfTimeAwayGoals *int                 `json:"half_time_away_goals,omitempty" example:"0"`
+	Events            []MatchEventResponse `json:"events"`
+	TotalEvents       int                  `json:"total_events" example:"14"`
+}
+
+// PlayerRequest defines the body for creating or updating a squad player.
+type PlayerRequest struct {
+	Name         string  `json:"name" binding:"required" example:"Mauro Icardi"`
+	ShirtNumber  uint    `json:"shirt_number" example:"9"`
+	Position     string  `json:"position" binding:"required,oneof=GK DF MF FW" example:"FW"`
+	Rating       int     `json:"rating" binding:"min=1,max=100" example:"85"`
+	ScoringShare float64 `json:"scoring_share" binding:"min=0" example:"0.25"`
+}
+
+// PlayerResponse defines the structure for a single squad player.
+type PlayerResponse struct {
+	ID           uint    `json:"id" example:"1"`
+	TeamID       uint    `json:"team_id" example:"1"`
+	Name         string  `json:"name" example:"Mauro Icardi"`
+	ShirtNumber  uint    `json:"shirt_number" example:"9"`
+	Position     string  `json:"position" example:"FW"`
+	Rating       int     `json:"rating" example:"85"`
+	ScoringShare float64 `json:"scoring_share" example:"0.25"`
+	Available    bool    `json:"available" example:"true"`
+	MatchesOut   uint    `json:"matches_out,omitempty" example:"2"`
+}
+
+// SquadResponse wraps a team's squad together with the effect of unavailable players on its strength.
+type SquadResponse struct {
+	TeamID        uint             `json:"team_id" example:"1"`
+	TeamName      string           `json:"team_name" example:"Team A"`
+	Players       []PlayerResponse `json:"players"`
+	TotalPlayers  int              `json:"total_players" example:"16"`
+	AttackFactor  float64          `json:"attack_factor" example:"0.95"`
+	DefenseFactor float64          `json:"defense_factor" example:"1"`
+}
+
+// ScorerResult holds a single row of the top scorers table.
+type ScorerResult struct {
+	PlayerID   uint   `json:"player_id" example:"9"`
+	PlayerName string `json:"player_name" example:"Mauro Icardi"`
+	TeamID     uint   `json:"team_id" example:"1"`
+	TeamName   string `json:"team_name" example:"Team A"`
+	Goals      int    `json:"goals" example:"5"`
+	Assists    int    `json:"assists" example:"2"`
+}
+
+// TopScorersResponse wraps the top scorers table.
+type TopScorersResponse struct {
+	Scorers      []ScorerResult `json:"scorers"`
+	TotalScorers int            `json:"total_scorers" example:"10"`
+}
+
+// WeekSimulationResponse is the response of simulating one or more weeks
+type WeekSimulationResponse struct {
+	Message     string                    `json:"message" example:"Next week successfully simulated"`
+	Success     bool                      `json:"success" example:"true"`
+	WeeksPlayed []uint                    `json:"weeks_played" example:"4"`
+	Results     []MatchSimulationResponse `json:"results"`
+	Standings   []models.Standing         `json:"standings"`
+	Predictions *PredictionsListResponse  `json:"predictions,omitempty"` // Only when predictions=true and matches remain
+}
+
+// MatchSimulationResponse defines the structure for a single simulated match.
+type MatchSimulationResponse struct {
+	MatchID    uint    `json:"match_id" example:"1"`
+	Week       uint    `json:"week" example:"1"`
+	HomeTeamID uint    `json:"home_team_id" example:"1"`
+	HomeTeam   string  `json:"home_team_name" example:"Team A"`
+	AwayTeamID uint    `json:"away_team_id" example:"2"`
+	AwayTeam   string  `json:"away_team_name" example:"Team B"`
+	HomeGoals  int     `json:"home_goals" example:"2"`
+	AwayGoals  int     `json:"away_goals" example:"1"`
+	HomeLambda float64 `json:"home_lambda" example:"1.85"`
+	AwayLambda float64 `json:"away_lambda" example:"1.12"`
+}
+
+// PlayUntilResponse wraps the results of playing the league up to a target week.
+type PlayUntilResponse struct {
+	Message   string                    `json:"message" example:"League played until week 4"`
+	Week      uint                      `json:"week" example:"4"`
+	Results   []MatchSimulationResponse `json:"results"`
+	Standings []models.Standing         `json:"standings"`
 }
 
 // PredictionResult holds information for a single team's prediction.
@@ -111,3 +238,113 @@ type ChampionPredictionResponse struct {
 	Method           string           `json:"method" example:"Monte Carlo Simulation (3,000 iterations)"`
 	Message          string           `json:"message" example:"Championship predictions calculated based on current standings"`
 }
+
+// RewindResponse is the response of rewinding the league to a previous week
+type RewindResponse struct {
+	Message   string                `json:"message" example:"League rewound to week 3"`
+	Week      uint                  `json:"week" example:"3"`
+	Snapshot  models.LeagueSnapshot `json:"snapshot"` // Snapshot taken before the rewind, restore it to undo
+	Standings []models.Standing     `json:"standings"`
+}
+
+// SnapshotRequest is the request body for creating a league snapshot
+type SnapshotRequest struct {
+	Label string `json:"label" binding:"max=255" example:"Before the derby"`
+}
+
+// SeasonAnalysisRequest is the request body for a season batch simulation
+type SeasonAnalysisRequest struct {
+	Seasons int   `json:"seasons" binding:"omitempty,min=1,max=100000" example:"10000"` // Seasons to simulate; 0 uses 10000
+	Seed    int64 `json:"seed" example:"42"`                                            // Random seed; 0 picks a random seed
+}
+
+// SnapshotsResponse lists the league snapshot history
+type SnapshotsResponse struct {
+	Snapshots      []models.LeagueSnapshot `json:"snapshots"`
+	TotalSnapshots int                     `json:"total_snapshots" example:"3"`
+}
+
+// RestoreResponse is the response of restoring a league snapshot
+type RestoreResponse struct {
+	Message   string                `json:"message" example:"Snapshot 2 restored"`
+	Snapshot  models.LeagueSnapshot `json:"snapshot"`
+	Standings []models.Standing     `json:"standings"`
+}
+
+// StreamWeekEvent is the payload of the "week_completed" stream event
+// Results holds the matches saved by the simulation that completed the week.
+type StreamWeekEvent struct {
+	Week    uint                      `json:"week" example:"4"`
+	Results []MatchSimulationResponse `json:"results"`
+}
+
+// StreamResetEvent is the payload of the "league_reset" stream event
+type StreamResetEvent struct {
+	Reason string `json:"reason" example:"rewind"`
+}
+
+// WebhookRequest is the request body for registering a webhook
+type WebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url,max=2048" example:"https://example.com/hooks/league"`
+	Secret string   `json:"secret" binding:"max=255" example:"s3cr3t"`    // Generated when empty
+	Events []string `json:"events" example:"week.completed,season.ended"` // Empty subscribes to every event
+}
+
+// WebhookResponse describes a registered webhook
+type WebhookResponse struct {
+	ID        uint      `json:"id" example:"1"`
+	URL       string    `json:"url" example:"https://example.com/hooks/league"`
+	Events    []string  `json:"events" example:"week.completed,season.ended"`
+	Active    bool      `json:"active" example:"true"`
+	Secret    string    `json:"secret,omitempty" example:"s3cr3t"` // Only returned when the webhook is registered
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhooksResponse lists the registered webhooks
+type WebhooksResponse struct {
+	Webhooks      []WebhookResponse `json:"webhooks"`
+	TotalWebhooks int               `json:"total_webhooks" example:"1"`
+}
+
+// WebhookDeliveriesResponse lists the deliveries of a webhook
+type WebhookDeliveriesResponse struct {
+	WebhookID       uint                     `json:"webhook_id" example:"1"`
+	Deliveries      []models.WebhookDelivery `json:"deliveries"`
+	TotalDeliveries int                      `json:"total_deliveries" example:"6"`
+}
+
+// JobRequest is the request body for submitting a background job
+type JobRequest struct {
+	Type   string          `json:"type" binding:"required" example:"predictions"`      // predictions or play_all
+	Params json.RawMessage `json:"params" swaggertype:"object" example:"{\"week\":4}"` // Parameters of the job type
+}
+
+// PredictionsJobParams are the parameters of a "predictions" job
+type PredictionsJobParams struct {
+	Week uint `json:"week" example:"4"` // Week to predict after; 0 uses the last completed week
+}
+
+// PlayAllJobParams are the parameters of a "play_all" job
+type PlayAllJobParams struct {
+	Predictions bool `json:"predictions" example:"true"` // Also refresh championship predictions
+}
+
+// JobResponse describes a background job
+type JobResponse struct {
+	ID         uint            `json:"id" example:"1"`
+	Type       string          `json:"type" example:"predictions"`
+	Status     string          `json:"status" example:"running"`
+	Progress   float64         `json:"progress" example:"45"`
+	Params     json.RawMessage `json:"params" swaggertype:"object"`
+	Result     json.RawMessage `json:"result,omitempty" swaggertype:"object"` // PredictionsListResponse or WeekSimulationResponse once succeeded; the weeks played so far for a stopped play_all job
+	Error      string          `json:"error,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	StartedAt  *time.Time      `json:"started_at,omitempty"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+}
+
+// JobsResponse lists background jobs
+type JobsResponse struct {
+	Jobs      []JobResponse `json:"jobs"`
+	TotalJobs int           `json:"total_jobs" example:"2"`
+}
@@ -2,6 +2,7 @@
 package api
 
 import (
+	"context"
 	"net/http"
 	"time"
 
@@ -11,9 +12,10 @@ import (
 	"github.com/tarikbacak/insider-league-simulator/internal/db"
 )
 
-// SetupRouter configures API routes and middlewares
+// Router configures API routes and middlewares
 // Defines RESTful API endpoints using Gin framework
-func SetupRouter() *gin.Engine { // Gin router'ını oluştur	// Use gin.SetMode(gin.ReleaseMode) in production environment
+// Use gin.SetMode(gin.ReleaseMode) in production environment
+func (s *Server) Router() *gin.Engine {
 	router := gin.Default()
 
 	// Add CORS middleware (for frontend integration)
@@ -23,32 +25,117 @@ func SetupRouter() *gin.Engine { // Gin router'ını oluştur	// Use gin.SetMode
 	router.Static("/web", "./web")
 	// Create API v1 route group
 	v1 := router.Group("/api/v1")
+	v1.Use(timeoutMiddleware(s.config.Server.RequestTimeout))
 	v1.Use(jsonMiddleware()) // JSON middleware only for API endpoints
-	{                        // Standings endpoint
+	{
+		// Standings endpoint
 		// GET /api/v1/standings - Returns current league standings
-		v1.GET("/standings", GetStandings)
+		v1.GET("/standings", s.GetStandings)
 
 		// Match listing endpoint
 		// GET /api/v1/matches?week=n - Returns matches for the specified week
 		// Returns all matches without query parameter
-		v1.GET("/matches", GetMatches)
+		v1.GET("/matches", s.GetMatches)
+
+		// Match timeline endpoint
+		// GET /api/v1/matches/:id/events - Returns the minute-by-minute events of a match
+		v1.GET("/matches/:id/events", s.GetMatchEvents)
+
+		// Simulation endpoints accept an Idempotency-Key header so retried requests
+		// do not advance the league twice
 
 		// Next week simulation endpoint
 		// POST /api/v1/matches/next - Simulates the next week
-		v1.POST("/matches/next", PlayNextWeek)
+		v1.POST("/matches/next", s.idempotencyMiddleware(), s.PlayNextWeek)
+
+		// Single match simulation endpoint
+		// POST /api/v1/matches/:id/simulate?force=true - Simulates one fixture (force re-simulates a played one)
+		v1.POST("/matches/:id/simulate", s.idempotencyMiddleware(), s.SimulateMatch)
+
+		// Play up to a target week endpoint
+		// POST /api/v1/matches/play-until?week=n - Simulates all weeks up to week n in one transaction
+		v1.POST("/matches/play-until", s.idempotencyMiddleware(), s.PlayUntilWeek)
+
+		// Rewind endpoint
+		// POST /api/v1/matches/rewind?week=n - Clears all results after week n (a snapshot is taken first)
+		v1.POST("/matches/rewind", s.RewindLeague)
 
 		// Simulate all remaining weeks endpoint
 		// POST /api/v1/matches/all - Simulates all remaining weeks
-		v1.POST("/matches/all", PlayAllWeeks) // Championship predictions endpoint
+		v1.POST("/matches/all", s.idempotencyMiddleware(), s.PlayAllWeeks)
 
+		// Championship predictions endpoint
 		// GET /api/v1/predictions?week=4|5 - Monte Carlo simulation for championship probabilities
-		v1.GET("/predictions", GetPredictions)
+		v1.GET("/predictions", s.GetPredictions)
+
+		// Squad endpoints
+		// GET/POST /api/v1/teams/:id/players - Lists or adds players of a team's squad
+		// GET/PUT/DELETE /api/v1/players/:id - Reads, updates or removes a player
+		v1.GET("/teams/:id/players", s.GetSquad)
+		v1.POST("/teams/:id/players", s.CreatePlayer)
+		v1.GET("/players/:id", s.GetPlayer)
+		v1.PUT("/players/:id", s.UpdatePlayer)
+		v1.DELETE("/players/:id", s.DeletePlayer)
+
+		// Top scorers endpoint
+		// GET /api/v1/stats/scorers?limit=n - Returns the top scorers table
+		v1.GET("/stats/scorers", s.GetTopScorers)
+
+		// Live updates endpoint
+		// GET /api/v1/stream - Server-Sent Events stream of results, standings and predictions
+		v1.GET("/stream", s.StreamEvents)
+
+		// Season analysis endpoint
+		// POST /api/v1/analysis/seasons - Simulates the full season many times and reports the distributions
+		v1.POST("/analysis/seasons", s.SimulateSeasons)
+
+		// Snapshot history endpoints
+		// GET/POST /api/v1/snapshots - Lists the snapshot history or saves the current league progress
+		// POST /api/v1/snapshots/:id/restore - Restores the league to a snapshot
+		v1.GET("/snapshots", s.GetSnapshots)
+		v1.POST("/snapshots", s.CreateSnapshot)
+		v1.POST("/snapshots/:id/restore", s.RestoreSnapshot)
+
+		// Background job endpoints
+		// GET/POST /api/v1/jobs - Lists or submits long-running operations (predictions, play_all)
+		// GET /api/v1/jobs/:id - Status, progress and result of a job
+		// POST /api/v1/jobs/:id/cancel - Cancels a queued or running job
+		v1.GET("/jobs", s.GetJobs)
+		v1.POST("/jobs", s.CreateJob)
+		v1.GET("/jobs/:id", s.GetJob)
+		v1.POST("/jobs/:id/cancel", s.CancelJob)
+
+		// Webhook endpoints
+		// GET/POST /api/v1/webhooks - Lists or registers webhooks for league events
+		// GET/DELETE /api/v1/webhooks/:id - Reads or removes a webhook
+		// GET /api/v1/webhooks/:id/deliveries - Delivery history of a webhook
+		// POST /api/v1/webhooks/:id/ping - Queues a test delivery
+		v1.GET("/webhooks", s.GetWebhooks)
+		v1.POST("/webhooks", s.CreateWebhook)
+		v1.GET("/webhooks/:id", s.GetWebhook)
+		v1.DELETE("/webhooks/:id", s.DeleteWebhook)
+		v1.GET("/webhooks/:id/deliveries", s.GetWebhookDeliveries)
+		v1.POST("/webhooks/:id/ping", s.PingWebhook)
+
+		// Import endpoint
+		// POST /api/v1/import - Replaces the league with the fixtures and results of a football-data CSV file
+		v1.POST("/import", s.ImportLeague)
+
+		// Export endpoint
+		// GET /api/v1/export?format=json|csv|xlsx - Downloads teams, fixtures, results, standings and predictions
+		v1.GET("/export", s.ExportLeague)
+
+		// League archive endpoints
+		// GET /api/v1/archive - Downloads the complete league state as a JSON archive
+		// POST /api/v1/archive/restore - Replaces the league with the content of an archive
+		v1.GET("/archive", s.GetArchive)
+		v1.POST("/archive/restore", s.RestoreArchive)
 
 		// Database initialization endpoint
 		// POST /api/v1/init - Resets and initializes database (for development)
-		v1.POST("/init", InitializeDatabase)
+		v1.POST("/init", s.InitializeDatabase)
 	} // Health check endpoint
-	router.GET("/health", jsonMiddleware(), HealthCheck)
+	router.GET("/health", jsonMiddleware(), s.HealthCheck)
 
 	// Swagger documentation
 	// @title Insider League Simulator API
@@ -66,7 +153,7 @@ func SetupRouter() *gin.Engine { // Gin router'ını oluştur	// Use gin.SetMode
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// Homepage endpoint - Returns API information
-	router.GET("/", jsonMiddleware(), GetAPIInfo)
+	router.GET("/", jsonMiddleware(), s.GetAPIInfo)
 
 	return router
 }
@@ -77,7 +164,8 @@ func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, "+IdempotencyKeyHeader)
+		c.Header("Access-Control-Expose-Headers", IdempotentReplayedHeader)
 
 		// For preflight requests
 		if c.Request.Method == "OPTIONS" {
@@ -97,6 +185,29 @@ func jsonMiddleware() gin.HandlerFunc {
 	}
 }
 
+// timeoutMiddleware gives each request a context that is cancelled after the timeout
+// Handlers pass the request context to the simulators and database queries, so
+// they stop once it expires and the error is answered with 504. Long-lived
+// routes such as the event stream are not limited. A zero timeout disables it.
+func timeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout <= 0 || untimedRoutes[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// untimedRoutes lists the routes that are not limited by the request timeout
+var untimedRoutes = map[string]bool{
+	"/api/v1/stream": true,
+}
+
 // HealthCheck simple health check endpoint
 // @Summary Health check
 // @Description Checks the health of the service
@@ -104,7 +215,7 @@ func jsonMiddleware() gin.HandlerFunc {
 // @Produce json
 // @Success 200 {object} HealthCheckResponse
 // @Router /health [get]
-func HealthCheck(c *gin.Context) {
+func (s *Server) HealthCheck(c *gin.Context) {
 	c.JSON(200, HealthCheckResponse{
 		Status:  "healthy",
 		Service: "insider-league-simulator",
@@ -126,17 +237,15 @@ func HealthCheck(c *gin.Context) {
 // @Success 200 {object} InitResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /init [post]
-func InitializeDatabase(c *gin.Context) {
+func (s *Server) InitializeDatabase(c *gin.Context) {
 	// Should only be used in development environment
-	if err := db.InitializeData(); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:     "Database initialization error",
-			Detail:    err.Error(),
-			Timestamp: time.Now().Format("2006-01-02 15:04:05"),
-		})
+	if err := db.ResetLeague(c.Request.Context(), s.store, db.SeedLeague); err != nil {
+		respondError(c, "Database initialization error", err)
 		return
 	}
 
+	s.publishLeagueReset("init")
+
 	c.JSON(http.StatusOK, InitResponse{
 		Message:   "Database initialized successfully",
 		Note:      "New random fixture generated",
@@ -151,20 +260,42 @@ func InitializeDatabase(c *gin.Context) {
 // @Produce json
 // @Success 200 {object} APIInfoResponse
 // @Router / [get]
-func GetAPIInfo(c *gin.Context) {
+func (s *Server) GetAPIInfo(c *gin.Context) {
 	c.JSON(http.StatusOK, APIInfoResponse{
 		Message: "Insider League Simulator API",
 		Version: "1.0.0",
 		Endpoints: map[string]string{
-			"standings":   "GET /api/v1/standings",
-			"matches":     "GET /api/v1/matches?week=n",
-			"next_week":   "POST /api/v1/matches/next",
-			"play_all":    "POST /api/v1/matches/all",
-			"predictions": "GET /api/v1/predictions?week=n",
-			"init_db":     "POST /api/v1/init",
-			"health":      "GET /health",
-			"swagger":     "GET /swagger/index.html",
-			"web_ui":      "GET /web/league.html",
+			"standings":    "GET /api/v1/standings",
+			"matches":      "GET /api/v1/matches?week=n",
+			"match_events": "GET /api/v1/matches/{id}/events",
+			"next_week":    "POST /api/v1/matches/next",
+			"play_match":   "POST /api/v1/matches/{id}/simulate?force=false",
+			"play_all":     "POST /api/v1/matches/all",
+			"play_until":   "POST /api/v1/matches/play-until?week=n",
+			"rewind":       "POST /api/v1/matches/rewind?week=n",
+			"snapshots":    "GET|POST /api/v1/snapshots",
+			"stream":       "GET /api/v1/stream",
+			"restore":      "POST /api/v1/snapshots/{id}/restore",
+			"predictions":  "GET /api/v1/predictions?week=n",
+			"squad":        "GET|POST /api/v1/teams/{id}/players",
+			"player":       "GET|PUT|DELETE /api/v1/players/{id}",
+			"top_scorers":  "GET /api/v1/stats/scorers",
+			"seasons":      "POST /api/v1/analysis/seasons",
+			"jobs":         "GET|POST /api/v1/jobs",
+			"job":          "GET /api/v1/jobs/{id}",
+			"cancel_job":   "POST /api/v1/jobs/{id}/cancel",
+			"webhooks":     "GET|POST /api/v1/webhooks",
+			"webhook":      "GET|DELETE /api/v1/webhooks/{id}",
+			"deliveries":   "GET /api/v1/webhooks/{id}/deliveries",
+			"ping_webhook": "POST /api/v1/webhooks/{id}/ping",
+			"import":       "POST /api/v1/import",
+			"export":       "GET /api/v1/export?format=json|csv|xlsx",
+			"archive":      "GET /api/v1/archive",
+			"load_archive": "POST /api/v1/archive/restore",
+			"init_db":      "POST /api/v1/init",
+			"health":       "GET /health",
+			"swagger":      "GET /swagger/index.html",
+			"web_ui":       "GET /web/league.html",
 		},
 	})
 }
@@ -0,0 +1,107 @@
+// Package api - API server and the services it depends on
+package api
+
+import (
+	"context"
+
+	"github.com/tarikbacak/insider-league-simulator/config"
+	"github.com/tarikbacak/insider-league-simulator/internal/db"
+	"github.com/tarikbacak/insider-league-simulator/internal/jobs"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/base"
+	"github.com/tarikbacak/insider-league-simulator/internal/stream"
+	"github.com/tarikbacak/insider-league-simulator/internal/webhook"
+	"gorm.io/gorm"
+)
+
+// Dependencies are the services the API handlers are built on
+// DB is required; every other field falls back to a default when left empty,
+// so tests and embedding applications only need to set what they replace.
+type Dependencies struct {
+	DB       *gorm.DB            // Database connection
+	Store    db.Store            // League repositories; the SQL store on DB when nil
+	Config   *config.Config      // Application configuration; zero values when nil
+	Hub      *stream.Hub         // Live update hub of GET /stream
+	Jobs     *jobs.Queue         // Background job queue of /jobs
+	Webhooks *webhook.Dispatcher // Outbound webhook dispatcher of /webhooks
+
+	// Observers are notified of the results and predictions saved through the
+	// server, in addition to the live stream and the webhook dispatcher
+	Observers base.Observers
+	// NewSimulator creates the match simulator; defaults to the Poisson simulator
+	NewSimulator func(store db.Store, observers base.Observers) base.Simulator
+	// NewPredictor creates the championship predictor; defaults to the Monte Carlo predictor
+	NewPredictor func(store db.Store, iterations int, observers base.Observers) base.Predictor
+}
+
+// Server holds the dependencies of the API handlers
+type Server struct {
+	db           *gorm.DB
+	store        db.Store
+	config       *config.Config
+	hub          *stream.Hub
+	jobs         *jobs.Queue
+	webhooks     *webhook.Dispatcher
+	observers    base.Observers
+	newSimulator func(store db.Store, observers base.Observers) base.Simulator
+	newPredictor func(store db.Store, iterations int, observers base.Observers) base.Predictor
+}
+
+// NewServer creates an API server from its dependencies
+// Simulation results are published to the server's hub, results and
+// predictions are queued as webhook events and the background job types are
+// registered with its queue.
+func NewServer(deps Dependencies) *Server {
+	s := &Server{
+		db:           deps.DB,
+		store:        deps.Store,
+		config:       deps.Config,
+		hub:          deps.Hub,
+		jobs:         deps.Jobs,
+		webhooks:     deps.Webhooks,
+		newSimulator: deps.NewSimulator,
+		newPredictor: deps.NewPredictor,
+	}
+	if s.store == nil {
+		s.store = db.NewSQLStore(s.db)
+	}
+	if s.config == nil {
+		s.config = &config.Config{}
+	}
+	if s.hub == nil {
+		s.hub = stream.NewHub()
+	}
+	if s.jobs == nil {
+		s.jobs = jobs.NewQueue(s.db, jobs.DefaultWorkers)
+	}
+	if s.webhooks == nil {
+		s.webhooks = webhook.NewDispatcher(s.db)
+	}
+	if s.newSimulator == nil {
+		s.newSimulator = simulator.GetPoissonSimulator
+	}
+	if s.newPredictor == nil {
+		s.newPredictor = simulator.GetMonteCarloPredictor
+	}
+
+	// Publish simulation results to live stream subscribers and webhooks
+	s.observers = base.Observers{Results: []base.ResultObserver{s.publishResults}}.
+		Merge(s.webhooks.Observers(s.store)).
+		Merge(deps.Observers)
+
+	// Register the operations that can be submitted as background jobs
+	s.jobs.Register(JobTypePredictions, s.runPredictionsJob)
+	s.jobs.Register(JobTypePlayAll, s.runPlayAllJob)
+
+	return s
+}
+
+// Start runs the background job workers and the webhook delivery worker
+// until ctx is cancelled
+func (s *Server) Start(ctx context.Context) error {
+	if err := s.jobs.Start(ctx); err != nil {
+		return err
+	}
+	s.webhooks.Start(ctx)
+	return nil
+}
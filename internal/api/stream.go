@@ -0,0 +1,125 @@
+// Package api - Server-Sent Events stream of live league updates
+package api
+
+import (
+	"context"
+	"io"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tarikbacak/insider-league-simulator/internal/db"
+	simModels "github.com/tarikbacak/insider-league-simulator/internal/simulator/models"
+	"github.com/tarikbacak/insider-league-simulator/internal/stream"
+)
+
+// streamHeartbeatInterval is how often a keep-alive comment is sent to idle clients
+const streamHeartbeatInterval = 15 * time.Second
+
+// StreamEvents streams live league updates as Server-Sent Events
+// @Summary Stream league updates
+// @Description Opens a Server-Sent Events stream. A "connected" event with the current standings is sent first, followed by "match_result", "week_completed", "standings", "predictions" and "league_reset" events as the league changes.
+// @Tags stream
+// @Produce text/event-stream
+// @Success 200 {object} stream.Event
+// @Router /stream [get]
+func (s *Server) StreamEvents(c *gin.Context) {
+	events, unsubscribe := s.hub.Subscribe()
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable proxy buffering
+
+	standings, err := db.CalculateStandings(c.Request.Context(), s.store)
+	if err != nil {
+		log.Printf("Could not calculate standings for stream: %v", err)
+	}
+	c.SSEvent(stream.EventConnected, StandingsResponse{
+		Standings:  standings,
+		TotalTeams: len(standings),
+	})
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(event.Type, event.Data)
+			return true
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": keep-alive\n\n")
+			return err == nil
+		}
+	})
+}
+
+// publishResults publishes committed simulation results and the updated standings
+// Registered as a simulator result observer, so it runs as results are saved.
+// week_completed is only published by the results that play the last unplayed
+// match of the week, not by a single match of an unfinished week or by a
+// forced re-simulation of a week that was already complete.
+func (s *Server) publishResults(week uint, results []simModels.MatchResult) {
+	if s.hub.SubscriberCount() == 0 {
+		return
+	}
+
+	ctx := context.Background()
+	teamNames, err := loadTeamNames(ctx, s.store.Teams())
+	if err != nil {
+		log.Printf("Could not publish results of week %d: %v", week, err)
+		return
+	}
+
+	responses := make([]MatchSimulationResponse, 0, len(results))
+	for _, result := range results {
+		response := toMatchSimulationResponse(result, teamNames)
+		s.hub.Publish(stream.EventMatchResult, response)
+		responses = append(responses, response)
+	}
+
+	if simModels.HasFirstPlays(results) {
+		unplayed, err := s.store.Matches().CountMatches(ctx, db.MatchFilter{Week: week, Played: db.Played(false)})
+		if err != nil {
+			log.Printf("Could not check whether week %d is complete: %v", week, err)
+		} else if unplayed == 0 {
+			s.hub.Publish(stream.EventWeekCompleted, StreamWeekEvent{
+				Week:    week,
+				Results: responses,
+			})
+		}
+	}
+
+	s.publishStandings()
+}
+
+// publishStandings publishes the current league table
+func (s *Server) publishStandings() {
+	if s.hub.SubscriberCount() == 0 {
+		return
+	}
+
+	standings, err := db.CalculateStandings(context.Background(), s.store)
+	if err != nil {
+		log.Printf("Could not publish standings: %v", err)
+		return
+	}
+	s.hub.Publish(stream.EventStandings, StandingsResponse{
+		Standings:  standings,
+		TotalTeams: len(standings),
+	})
+}
+
+// publishLeagueReset tells clients that results were reset and publishes the new standings
+func (s *Server) publishLeagueReset(reason string) {
+	s.hub.Publish(stream.EventLeagueReset, StreamResetEvent{Reason: reason})
+	s.publishStandings()
+}
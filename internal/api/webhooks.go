@@ -0,0 +1,244 @@
+// Package api - Webhook registration and delivery handler functions
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+	"github.com/tarikbacak/insider-league-simulator/internal/webhook"
+)
+
+// CreateWebhook registers a webhook
+// @Summary Register webhook
+// @Description Registers an endpoint that is POSTed league events: "week.completed", "season.ended" and "champion.changed" (plus "ping" on request). Each delivery is signed with the webhook secret: the X-League-Signature header is "sha256=" followed by the hex HMAC-SHA256 of the X-League-Timestamp header, a dot and the raw body. The secret is only returned in this response; a random one is generated if none is given. Failed deliveries are retried with exponential backoff.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body WebhookRequest true "Webhook data"
+// @Success 201 {object} WebhookResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /webhooks [post]
+func (s *Server) CreateWebhook(c *gin.Context) {
+	var request WebhookRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalid(c, "Invalid webhook data", err.Error())
+		return
+	}
+	if !strings.HasPrefix(request.URL, "http://") && !strings.HasPrefix(request.URL, "https://") {
+		respondInvalid(c, "Invalid webhook data", "URL must use http or https.")
+		return
+	}
+	for _, event := range request.Events {
+		if !webhook.IsValidEvent(event) {
+			respondInvalid(c, "Invalid webhook data",
+				"Unknown event "+strconv.Quote(event)+"; valid events are "+strings.Join(webhook.Events, ", ")+".")
+			return
+		}
+	}
+
+	if request.Secret == "" {
+		secret, err := webhook.GenerateSecret()
+		if err != nil {
+			respondError(c, "Could not generate webhook secret", err)
+			return
+		}
+		request.Secret = secret
+	}
+
+	hook := models.Webhook{
+		URL:    request.URL,
+		Secret: request.Secret,
+		Events: webhook.JoinEvents(request.Events),
+		Active: true,
+	}
+	if err := s.webhooks.Register(c.Request.Context(), &hook); err != nil {
+		respondError(c, "Could not register webhook", err)
+		return
+	}
+
+	response := toWebhookResponse(hook)
+	response.Secret = hook.Secret
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetWebhooks returns the registered webhooks
+// @Summary List webhooks
+// @Description Returns every registered webhook (secrets are not included)
+// @Tags webhooks
+// @Produce json
+// @Success 200 {object} WebhooksResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /webhooks [get]
+func (s *Server) GetWebhooks(c *gin.Context) {
+	hooks, err := s.webhooks.List(c.Request.Context())
+	if err != nil {
+		respondError(c, "Could not retrieve webhooks", err)
+		return
+	}
+
+	responses := make([]WebhookResponse, 0, len(hooks))
+	for _, hook := range hooks {
+		responses = append(responses, toWebhookResponse(hook))
+	}
+
+	c.JSON(http.StatusOK, WebhooksResponse{
+		Webhooks:      responses,
+		TotalWebhooks: len(responses),
+	})
+}
+
+// GetWebhook returns a single webhook
+// @Summary Get webhook
+// @Description Returns a registered webhook (the secret is not included)
+// @Tags webhooks
+// @Produce json
+// @Param id path integer true "Webhook ID"
+// @Success 200 {object} WebhookResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /webhooks/{id} [get]
+func (s *Server) GetWebhook(c *gin.Context) {
+	hook, ok := s.findWebhook(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, toWebhookResponse(hook))
+}
+
+// DeleteWebhook removes a webhook
+// @Summary Delete webhook
+// @Description Removes a webhook together with its delivery history; pending deliveries are not sent
+// @Tags webhooks
+// @Produce json
+// @Param id path integer true "Webhook ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /webhooks/{id} [delete]
+func (s *Server) DeleteWebhook(c *gin.Context) {
+	hook, ok := s.findWebhook(c)
+	if !ok {
+		return
+	}
+
+	if err := s.webhooks.Remove(c.Request.Context(), hook); err != nil {
+		respondError(c, "Could not delete webhook", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries returns the delivery history of a webhook
+// @Summary List webhook deliveries
+// @Description Returns the deliveries queued for a webhook, newest first, with their status, attempts and last error
+// @Tags webhooks
+// @Produce json
+// @Param id path integer true "Webhook ID"
+// @Param status query string false "Delivery status" Enums(pending, delivered, failed)
+// @Param limit query integer false "Maximum number of deliveries to return (1-100, default 50)"
+// @Success 200 {object} WebhookDeliveriesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /webhooks/{id}/deliveries [get]
+func (s *Server) GetWebhookDeliveries(c *gin.Context) {
+	hook, ok := s.findWebhook(c)
+	if !ok {
+		return
+	}
+
+	limit := 50
+	if limitParam := c.Query("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed < 1 || parsed > 100 {
+			respondInvalid(c, "Invalid limit parameter", "Limit must be a number between 1 and 100.")
+			return
+		}
+		limit = parsed
+	}
+
+	status := c.Query("status")
+	switch status {
+	case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryFailed:
+	default:
+		respondInvalid(c, "Invalid status parameter", "Status must be pending, delivered or failed.")
+		return
+	}
+
+	deliveries, err := s.webhooks.Deliveries(c.Request.Context(), hook.ID, status, limit)
+	if err != nil {
+		respondError(c, "Could not retrieve webhook deliveries", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, WebhookDeliveriesResponse{
+		WebhookID:       hook.ID,
+		Deliveries:      deliveries,
+		TotalDeliveries: len(deliveries),
+	})
+}
+
+// PingWebhook queues a test delivery to a webhook
+// @Summary Ping webhook
+// @Description Queues a signed "ping" event for the webhook so the receiver can be tested. The delivery is sent in the background; check its status in the delivery history.
+// @Tags webhooks
+// @Produce json
+// @Param id path integer true "Webhook ID"
+// @Success 202 {object} models.WebhookDelivery
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /webhooks/{id}/ping [post]
+func (s *Server) PingWebhook(c *gin.Context) {
+	hook, ok := s.findWebhook(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := s.webhooks.Ping(hook)
+	if err != nil {
+		respondError(c, "Could not queue ping", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// findWebhook loads the webhook given by the :id path parameter, writing an error response if it fails
+func (s *Server) findWebhook(c *gin.Context) (models.Webhook, bool) {
+	webhookID, ok := parseIDParam(c, "Webhook")
+	if !ok {
+		return models.Webhook{}, false
+	}
+
+	hook, err := s.webhooks.Find(c.Request.Context(), webhookID)
+	if err != nil {
+		respondError(c, "Could not retrieve webhook", err)
+		return hook, false
+	}
+
+	return hook, true
+}
+
+// toWebhookResponse converts a webhook to its API representation without the secret
+func toWebhookResponse(hook models.Webhook) WebhookResponse {
+	events := webhook.SubscribedEvents(hook)
+	if events == nil {
+		events = webhook.Events
+	}
+	return WebhookResponse{
+		ID:        hook.ID,
+		URL:       hook.URL,
+		Events:    events,
+		Active:    hook.Active,
+		CreatedAt: hook.CreatedAt,
+	}
+}
@@ -0,0 +1,464 @@
+// Package archive saves the complete state of a league as a self-describing
+// JSON document and restores it, in the same or in another instance
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/db"
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+	simModels "github.com/tarikbacak/insider-league-simulator/internal/simulator/models"
+	"gorm.io/gorm"
+)
+
+// Archive identification
+const (
+	Format  = "insider-league-archive" // Value of the format field of every archive
+	Version = 1                        // Layout version; increased on incompatible changes
+)
+
+// ErrInvalidArchive is returned for documents that are not a valid league archive
+var ErrInvalidArchive = errors.New("invalid league archive")
+
+// Archive is the complete state of a league
+// Records keep their IDs, so a restored league answers the same URLs and
+// references as the archived one. With the random seed and the same match
+// model, the restored league also plays the same results from now on.
+type Archive struct {
+	Format      string                 `json:"format" example:"insider-league-archive"` // Always "insider-league-archive"
+	Version     int                    `json:"version" example:"1"`                     // Layout version of the archive
+	CreatedAt   time.Time              `json:"created_at"`                              // When the archive was created
+	Week        uint                   `json:"week" example:"3"`                        // Last completed week
+	Model       Model                  `json:"model"`                                   // Match model parameters of the archiving instance
+	Seed        *Seed                  `json:"seed"`                                    // Random state of the matches; nil if no match model seed was chosen
+	Teams       []Team                 `json:"teams"`                                   // Teams with their statistics and squads
+	Matches     []Match                `json:"matches"`                                 // The fixture with its results
+	Events      []models.MatchEvent    `json:"match_events"`                            // Timelines of the played matches
+	Absences    []models.PlayerAbsence `json:"player_absences"`                         // Injuries and suspensions
+	Predictions []models.Prediction    `json:"predictions"`                             // Championship predictions of all weeks
+}
+
+// Model holds the parameters of the Poisson match model
+type Model struct {
+	Name          string  `json:"name" example:"poisson"`
+	LeagueAverage float64 `json:"league_average" example:"1.5"` // Goals per team per match (λ_league)
+	MinLambda     float64 `json:"min_lambda" example:"0.1"`
+	MaxLambda     float64 `json:"max_lambda" example:"4"`
+	HomeAdvantage float64 `json:"home_advantage" example:"0.15"` // Largest random home advantage
+	FormFactorMin float64 `json:"form_factor_min" example:"0.8"`
+	FormFactorMax float64 `json:"form_factor_max" example:"1.2"`
+	MaxGoals      int     `json:"max_goals" example:"8"` // Most goals of a team in a match
+}
+
+// Seed is the random state that the matches of the league are simulated with
+type Seed struct {
+	Value   int64  `json:"value" example:"1718040000000000000"` // Seed chosen when the league was created
+	Matches uint64 `json:"matches" example:"6"`                 // Matches simulated with the seed so far
+}
+
+// Team is an archived team
+type Team struct {
+	ID      uint       `json:"id" example:"1"`
+	Name    string     `json:"name" example:"Galatasaray"`
+	Attack  int        `json:"attack" example:"80"`
+	Defense int        `json:"defense" example:"75"`
+	Stats   *TeamStats `json:"stats"` // nil if the team has no statistics
+	Players []Player   `json:"players"`
+}
+
+// TeamStats are the archived statistics of a team
+type TeamStats struct {
+	Played          uint    `json:"played" example:"3"`
+	Won             uint    `json:"won" example:"2"`
+	Drawn           uint    `json:"drawn" example:"1"`
+	Lost            uint    `json:"lost" example:"0"`
+	GoalsFor        uint    `json:"goals_for" example:"7"`
+	GoalsAgainst    uint    `json:"goals_against" example:"2"`
+	Points          uint    `json:"points" example:"7"`
+	AvgScored       float64 `json:"avg_scored" example:"0.8"`
+	AvgConceded     float64 `json:"avg_conceded" example:"0.25"`
+	AttackStrength  float64 `json:"attack_strength" example:"1.07"`
+	DefenseStrength float64 `json:"defense_strength" example:"1"`
+}
+
+// Player is an archived squad member
+// Removed players are archived too, since the timelines and absences of the
+// matches they played still refer to them.
+type Player struct {
+	ID           uint       `json:"id" example:"1"`
+	Name         string     `json:"name" example:"Fernando Muslera"`
+	ShirtNumber  uint       `json:"shirt_number" example:"1"`
+	Position     string     `json:"position" example:"GK"`
+	Rating       int        `json:"rating" example:"78"`
+	ScoringShare float64    `json:"scoring_share" example:"0"`
+	RemovedAt    *time.Time `json:"removed_at,omitempty"` // When the player was removed from the squad; nil for current players
+}
+
+// Match is an archived fixture with its result
+type Match struct {
+	ID         uint      `json:"id" example:"1"`
+	Week       uint      `json:"week" example:"1"`
+	HomeTeamID uint      `json:"home_team_id" example:"1"`
+	AwayTeamID uint      `json:"away_team_id" example:"2"`
+	HomeGoals  *uint     `json:"home_goals" example:"2"` // nil if the match has not been played
+	AwayGoals  *uint     `json:"away_goals" example:"1"` // nil if the match has not been played
+	PlayedAt   time.Time `json:"played_at"`
+}
+
+// Summary counts the records of a restored archive
+type Summary struct {
+	Teams       int      `json:"teams" example:"4"`
+	Players     int      `json:"players" example:"64"` // Current squad members, removed players not counted
+	Matches     int      `json:"matches" example:"12"`
+	Played      int      `json:"played" example:"6"`
+	Week        uint     `json:"week" example:"3"`
+	Events      int      `json:"match_events" example:"84"`
+	Absences    int      `json:"player_absences" example:"2"`
+	Predictions int      `json:"predictions" example:"4"`
+	Warnings    []string `json:"warnings"` // Differences that may make the restored league behave differently
+}
+
+// CurrentModel returns the match model parameters of this instance
+func CurrentModel() Model {
+	return Model{
+		Name:          "poisson",
+		LeagueAverage: simModels.LeagueAverage,
+		MinLambda:     simModels.MinLambda,
+		MaxLambda:     simModels.MaxLambda,
+		HomeAdvantage: simModels.HomeAdvantage,
+		FormFactorMin: simModels.FormFactorMin,
+		FormFactorMax: simModels.FormFactorMax,
+		MaxGoals:      simModels.MaxGoals,
+	}
+}
+
+// Build reads the complete league in store
+func Build(ctx context.Context, store db.Store) (*Archive, error) {
+	state, err := store.ReadLeagueState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &Archive{
+		Format:      Format,
+		Version:     Version,
+		CreatedAt:   time.Now().UTC(),
+		Model:       CurrentModel(),
+		Teams:       make([]Team, 0, len(state.Teams)),
+		Matches:     make([]Match, 0, len(state.Matches)),
+		Events:      state.Events,
+		Absences:    state.Absences,
+		Predictions: state.Predictions,
+	}
+	if state.Seed != nil {
+		archive.Seed = &Seed{Value: state.Seed.Seed, Matches: state.Seed.Matches}
+	}
+
+	stats := make(map[uint]*TeamStats, len(state.Stats))
+	for _, s := range state.Stats {
+		stats[s.TeamID] = &TeamStats{
+			Played:          s.Played,
+			Won:             s.Won,
+			Drawn:           s.Drawn,
+			Lost:            s.Lost,
+			GoalsFor:        s.GoalsFor,
+			GoalsAgainst:    s.GoalsAway,
+			Points:          s.Points,
+			AvgScored:       s.AvgScored,
+			AvgConceded:     s.AvgConceded,
+			AttackStrength:  s.AttackStrength,
+			DefenseStrength: s.DefenseStrength,
+		}
+	}
+	players := make(map[uint][]Player, len(state.Teams))
+	for _, p := range state.Players {
+		player := Player{
+			ID:           p.ID,
+			Name:         p.Name,
+			ShirtNumber:  p.ShirtNumber,
+			Position:     p.Position,
+			Rating:       p.Rating,
+			ScoringShare: p.ScoringShare,
+		}
+		if p.DeletedAt.Valid {
+			removedAt := p.DeletedAt.Time
+			player.RemovedAt = &removedAt
+		}
+		players[p.TeamID] = append(players[p.TeamID], player)
+	}
+	for _, team := range state.Teams {
+		squad := players[team.ID]
+		if squad == nil {
+			squad = []Player{}
+		}
+		archive.Teams = append(archive.Teams, Team{
+			ID:      team.ID,
+			Name:    team.Name,
+			Attack:  team.Attack,
+			Defense: team.Defense,
+			Stats:   stats[team.ID],
+			Players: squad,
+		})
+	}
+
+	for _, match := range state.Matches {
+		archive.Matches = append(archive.Matches, Match{
+			ID:         match.ID,
+			Week:       match.Week,
+			HomeTeamID: match.HomeTeamID,
+			AwayTeamID: match.AwayTeamID,
+			HomeGoals:  match.HomeGoals,
+			AwayGoals:  match.AwayGoals,
+			PlayedAt:   match.PlayedAt,
+		})
+		if match.HomeGoals != nil && match.AwayGoals != nil {
+			archive.Week = max(archive.Week, match.Week)
+		}
+	}
+	return archive, nil
+}
+
+// Write writes an archive as indented JSON
+func Write(w io.Writer, archive *Archive) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(archive)
+}
+
+// Read decodes and validates an archive
+func Read(r io.Reader) (*Archive, error) {
+	var archive Archive
+	if err := json.NewDecoder(r).Decode(&archive); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+	if err := Validate(&archive); err != nil {
+		return nil, err
+	}
+	return &archive, nil
+}
+
+// Restore replaces the league in store, including its snapshots, with the
+// archived one. Match results, timelines, absences, predictions and the random
+// seed are restored as they were archived, so matches played afterwards have
+// the same results as in the archiving instance. They use the match model of
+// this instance, which is reported in Summary.Warnings if it differs, as is a
+// missing seed. Championship predictions are Monte Carlo estimates and vary
+// between runs in any instance.
+func Restore(ctx context.Context, store db.Store, archive *Archive) (*Summary, error) {
+	if err := Validate(archive); err != nil {
+		return nil, err
+	}
+
+	summary := &Summary{
+		Teams:       len(archive.Teams),
+		Matches:     len(archive.Matches),
+		Events:      len(archive.Events),
+		Absences:    len(archive.Absences),
+		Predictions: len(archive.Predictions),
+		Warnings:    []string{},
+	}
+	if archive.Model != CurrentModel() {
+		summary.Warnings = append(summary.Warnings,
+			"the archive was created with other match model parameters; matches played from now on use the parameters of this instance")
+	}
+	seed := db.NewLeagueSeed()
+	if archive.Seed != nil {
+		seed = &models.LeagueSeed{Seed: archive.Seed.Value, Matches: archive.Seed.Matches}
+	} else {
+		summary.Warnings = append(summary.Warnings,
+			"the archive has no random seed; matches played from now on use a new seed and have other results than in the archiving instance")
+	}
+
+	state := &db.LeagueState{
+		Teams:       make([]models.Team, 0, len(archive.Teams)),
+		Stats:       make([]models.TeamStats, 0, len(archive.Teams)),
+		Matches:     make([]models.Match, 0, len(archive.Matches)),
+		Events:      archive.Events,
+		Absences:    archive.Absences,
+		Predictions: archive.Predictions,
+		Seed:        seed,
+	}
+	for _, team := range archive.Teams {
+		state.Teams = append(state.Teams, models.Team{
+			Model:   gorm.Model{ID: team.ID},
+			Name:    team.Name,
+			Attack:  team.Attack,
+			Defense: team.Defense,
+		})
+		if s := team.Stats; s != nil {
+			state.Stats = append(state.Stats, models.TeamStats{
+				TeamID:          team.ID,
+				Played:          s.Played,
+				Won:             s.Won,
+				Drawn:           s.Drawn,
+				Lost:            s.Lost,
+				GoalsFor:        s.GoalsFor,
+				GoalsAway:       s.GoalsAgainst,
+				Points:          s.Points,
+				AvgScored:       s.AvgScored,
+				AvgConceded:     s.AvgConceded,
+				AttackStrength:  s.AttackStrength,
+				DefenseStrength: s.DefenseStrength,
+			})
+		}
+		for _, p := range team.Players {
+			player := models.Player{
+				Model:        gorm.Model{ID: p.ID},
+				TeamID:       team.ID,
+				Name:         p.Name,
+				ShirtNumber:  p.ShirtNumber,
+				Position:     p.Position,
+				Rating:       p.Rating,
+				ScoringShare: p.ScoringShare,
+			}
+			if p.RemovedAt != nil {
+				player.DeletedAt = gorm.DeletedAt{Time: *p.RemovedAt, Valid: true}
+			} else {
+				summary.Players++
+			}
+			state.Players = append(state.Players, player)
+		}
+	}
+	for _, match := range archive.Matches {
+		state.Matches = append(state.Matches, models.Match{
+			Model:      gorm.Model{ID: match.ID},
+			Week:       match.Week,
+			HomeTeamID: match.HomeTeamID,
+			AwayTeamID: match.AwayTeamID,
+			HomeGoals:  match.HomeGoals,
+			AwayGoals:  match.AwayGoals,
+			PlayedAt:   match.PlayedAt,
+		})
+		if match.HomeGoals != nil {
+			summary.Played++
+			summary.Week = max(summary.Week, match.Week)
+		}
+	}
+
+	if err := store.ReplaceLeagueState(ctx, state); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// Validate checks that an archive has a supported version and that its
+// records have unique IDs and refer only to records of the archive
+func Validate(archive *Archive) error {
+	switch {
+	case archive.Format != Format:
+		return fmt.Errorf("%w: format is %q instead of %q", ErrInvalidArchive, archive.Format, Format)
+	case archive.Version < 1 || archive.Version > Version:
+		return fmt.Errorf("%w: version %d is not supported (1 to %d)", ErrInvalidArchive, archive.Version, Version)
+	case len(archive.Teams) == 0:
+		return fmt.Errorf("%w: the archive has no teams", ErrInvalidArchive)
+	}
+
+	teams, players, matches := make(idSet), make(idSet), make(idSet)
+	names := make(map[string]bool, len(archive.Teams))
+	for _, team := range archive.Teams {
+		if err := teams.add("team", team.ID); err != nil {
+			return err
+		}
+		if team.Name == "" || names[team.Name] {
+			return fmt.Errorf("%w: team %d has an empty or repeated name", ErrInvalidArchive, team.ID)
+		}
+		names[team.Name] = true
+		for _, player := range team.Players {
+			if err := players.add("player", player.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, match := range archive.Matches {
+		if err := matches.add("match", match.ID); err != nil {
+			return err
+		}
+		switch {
+		case match.Week < 1:
+			return fmt.Errorf("%w: match %d has week 0", ErrInvalidArchive, match.ID)
+		case match.HomeTeamID == match.AwayTeamID:
+			return fmt.Errorf("%w: match %d has the same home and away team", ErrInvalidArchive, match.ID)
+		case (match.HomeGoals == nil) != (match.AwayGoals == nil):
+			return fmt.Errorf("%w: match %d has only one score", ErrInvalidArchive, match.ID)
+		}
+		if err := teams.check("match", match.ID, "team", match.HomeTeamID, match.AwayTeamID); err != nil {
+			return err
+		}
+	}
+
+	events := make(idSet)
+	for _, event := range archive.Events {
+		if err := events.add("match event", event.ID); err != nil {
+			return err
+		}
+		if err := matches.check("match event", event.ID, "match", event.MatchID); err != nil {
+			return err
+		}
+		for _, ref := range []struct {
+			set    idSet
+			target string
+			id     *uint
+		}{{teams, "team", event.TeamID}, {players, "player", event.PlayerID}, {players, "player", event.AssistPlayerID}} {
+			if ref.id != nil {
+				if err := ref.set.check("match event", event.ID, ref.target, *ref.id); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	absences := make(idSet)
+	for _, absence := range archive.Absences {
+		if err := absences.add("player absence", absence.ID); err != nil {
+			return err
+		}
+		if err := players.check("player absence", absence.ID, "player", absence.PlayerID); err != nil {
+			return err
+		}
+		if err := teams.check("player absence", absence.ID, "team", absence.TeamID); err != nil {
+			return err
+		}
+		if err := matches.check("player absence", absence.ID, "match", absence.MatchID); err != nil {
+			return err
+		}
+	}
+
+	predictions := make(idSet)
+	for _, prediction := range archive.Predictions {
+		if err := predictions.add("prediction", prediction.ID); err != nil {
+			return err
+		}
+		if err := teams.check("prediction", prediction.ID, "team", prediction.TeamID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// idSet holds the IDs of the archived records of one table
+type idSet map[uint]bool
+
+// add records the ID of a record; IDs must be set and unique
+func (s idSet) add(record string, id uint) error {
+	if id == 0 || s[id] {
+		return fmt.Errorf("%w: %s ID %d is missing or repeated", ErrInvalidArchive, record, id)
+	}
+	s[id] = true
+	return nil
+}
+
+// check reports a reference of a record to a target ID that is not in the set
+func (s idSet) check(record string, recordID uint, target string, ids ...uint) error {
+	for _, id := range ids {
+		if !s[id] {
+			return fmt.Errorf("%w: %s %d refers to unknown %s %d", ErrInvalidArchive, record, recordID, target, id)
+		}
+	}
+	return nil
+}
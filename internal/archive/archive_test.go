@@ -0,0 +1,102 @@
+package archive_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/tarikbacak/insider-league-simulator/config"
+	"github.com/tarikbacak/insider-league-simulator/internal/archive"
+	"github.com/tarikbacak/insider-league-simulator/internal/db"
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/poisson"
+)
+
+// TestArchiveWithRemovedScorer archives a played season after a goal scorer
+// was removed from his squad, and restores it into a fresh league
+func TestArchiveWithRemovedScorer(t *testing.T) {
+	stores := map[string]func(t *testing.T) db.Store{
+		"sqlite": func(t *testing.T) db.Store {
+			database, err := db.Open(&config.Config{Database: config.DatabaseConfig{
+				Driver:      config.DriverSQLite,
+				Path:        t.TempDir() + "/league.db",
+				AutoMigrate: true,
+			}})
+			if err != nil {
+				t.Fatalf("open database: %v", err)
+			}
+			return db.NewSQLStore(database)
+		},
+		"memory": func(t *testing.T) db.Store {
+			return db.NewMemoryStore()
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+			if err := db.ResetLeague(ctx, store, db.SeedLeague); err != nil {
+				t.Fatalf("seed league: %v", err)
+			}
+			if _, err := poisson.NewPoissonSimulator(store).PlayAllRemainingWeeks(ctx, nil); err != nil {
+				t.Fatalf("play season: %v", err)
+			}
+
+			goals, err := store.Matches().ListEvents(ctx, db.EventFilter{Type: models.EventGoal})
+			if err != nil {
+				t.Fatalf("list goals: %v", err)
+			}
+			var scorerID uint
+			for _, goal := range goals {
+				if goal.PlayerID != nil {
+					scorerID = *goal.PlayerID
+					break
+				}
+			}
+			if scorerID == 0 {
+				t.Skip("no goal with a scorer was played")
+			}
+			if err := store.Teams().DeletePlayer(ctx, scorerID); err != nil {
+				t.Fatalf("delete player %d: %v", scorerID, err)
+			}
+
+			built, err := archive.Build(ctx, store)
+			if err != nil {
+				t.Fatalf("build archive: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := archive.Write(&buf, built); err != nil {
+				t.Fatalf("write archive: %v", err)
+			}
+			read, err := archive.Read(&buf)
+			if err != nil {
+				t.Fatalf("read archive: %v", err)
+			}
+			if err := archive.Validate(read); err != nil {
+				t.Fatalf("validate archive: %v", err)
+			}
+
+			restored := newStore(t)
+			summary, err := archive.Restore(ctx, restored, read)
+			if err != nil {
+				t.Fatalf("restore archive: %v", err)
+			}
+			if _, err := restored.Teams().FindPlayer(ctx, scorerID); err == nil {
+				t.Errorf("removed player %d is back in the squad", scorerID)
+			}
+			players, err := restored.Teams().FindPlayers(ctx, []uint{scorerID})
+			if err != nil || len(players) != 1 {
+				t.Errorf("removed player %d was not restored: %v", scorerID, err)
+			}
+
+			state, err := store.ReadLeagueState(ctx)
+			if err != nil {
+				t.Fatalf("read league: %v", err)
+			}
+			if want := len(state.Players) - 1; summary.Players != want {
+				t.Errorf("summary counts %d players, want %d", summary.Players, want)
+			}
+		})
+	}
+}
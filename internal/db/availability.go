@@ -0,0 +1,36 @@
+package db
+
+import (
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+	"gorm.io/gorm"
+)
+
+// GetUnavailablePlayers returns the injured or suspended players of a team,
+// mapped to the number of team matches they will still miss
+func GetUnavailablePlayers(database *gorm.DB, teamID uint) (map[uint]uint, error) {
+	var absences []models.PlayerAbsence
+	if err := database.Where("team_id = ?", teamID).Find(&absences).Error; err != nil {
+		return nil, err
+	}
+
+	remaining := make(map[uint]uint)
+	if len(absences) == 0 {
+		return remaining, nil
+	}
+
+	// Weeks in which the team has already played, used to count matches served
+	var playedWeeks []uint
+	if err := database.Model(&models.Match{}).
+		Where("(home_team_id = ? OR away_team_id = ?) AND home_goals IS NOT NULL AND away_goals IS NOT NULL", teamID, teamID).
+		Pluck("week", &playedWeeks).Error; err != nil {
+		return nil, err
+	}
+
+	for _, absence := range absences {
+		if matches := absence.RemainingMatches(playedWeeks); matches > remaining[absence.PlayerID] {
+			remaining[absence.PlayerID] = matches
+		}
+	}
+
+	return remaining, nil
+}
@@ -1,54 +1,130 @@
 package db
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
+	"github.com/glebarez/sqlite"
 	"github.com/tarikbacak/insider-league-simulator/config"
 	"github.com/tarikbacak/insider-league-simulator/internal/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-// DB is the global database connection object
-var DB *gorm.DB
+// Open connects to the database described by cfg and, unless automatic
+// migrations are disabled, applies its pending schema migrations
+// The returned connection is passed explicitly to the packages that need it.
+func Open(cfg *config.Config) (*gorm.DB, error) {
+	database, err := Connect(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.Database.AutoMigrate {
+		return database, nil
+	}
+
+	if err := Migrate(database); err != nil {
+		return nil, err
+	}
+	log.Println("Database migration completed successfully")
+	return database, nil
+}
 
-// InitDB initializes the database connection and performs auto-migration using GORM models
-// It uses the config package to get connection info from the .env file
-func InitDB() {
-	cfg := config.GetConfig()
+// maxConnectBackoff caps the wait between two connection attempts
+const maxConnectBackoff = 30 * time.Second
 
-	var err error
-	// Connect to the database using GORM
-	DB, err = gorm.Open(postgres.Open(cfg.GetDatabaseURL()), &gorm.Config{})
+// Connect connects to the database described by cfg without migrating it
+// A failed connection is retried cfg.Database.ConnectRetries times, waiting
+// ConnectBackoff before the first retry and twice as long before each next one,
+// so the server can start before its database is ready.
+func Connect(cfg *config.Config) (*gorm.DB, error) {
+	dialector, location, err := dialectorFor(cfg)
 	if err != nil {
-		log.Fatalf("Error connecting to database: %v", err)
+		return nil, err
 	}
 
-	// Auto-Migration: Automatically create/update tables
-	err = DB.AutoMigrate(&models.Team{}, &models.Match{}, &models.TeamStats{}, &models.Prediction{})
+	// Connect to the database using GORM; opening it pings the server
+	var database *gorm.DB
+	backoff := cfg.Database.ConnectBackoff
+	for attempt := 0; ; attempt++ {
+		database, err = gorm.Open(dialector, &gorm.Config{})
+		if err == nil {
+			break
+		}
+		if attempt >= cfg.Database.ConnectRetries {
+			return nil, fmt.Errorf("error connecting to database after %d attempts: %w", attempt+1, err)
+		}
+		log.Printf("Warning: could not connect to database (attempt %d of %d), retrying in %s: %v",
+			attempt+1, cfg.Database.ConnectRetries+1, backoff, err)
+		time.Sleep(backoff)
+		backoff = min(backoff*2, maxConnectBackoff)
+	}
+
+	sqlDB, err := database.DB()
 	if err != nil {
-		log.Fatalf("Auto-migration error: %v", err)
+		return nil, fmt.Errorf("error connecting to database: %w", err)
+	}
+	if cfg.Database.Driver == config.DriverSQLite {
+		// SQLite allows a single writer and every connection to ":memory:"
+		// opens a separate database, so all queries share one connection
+		sqlDB.SetMaxOpenConns(1)
+	} else {
+		sqlDB.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+		sqlDB.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+		sqlDB.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
+		sqlDB.SetConnMaxIdleTime(cfg.Database.ConnMaxIdleTime)
 	}
 
-	fmt.Printf("Database connection and migration completed successfully - %s:%s\n", cfg.Database.Host, cfg.Database.Port)
+	log.Printf("Database connection completed successfully - %s", location)
+	return database, nil
 }
 
-// GetDB returns the global database connection object
-func GetDB() *gorm.DB {
-	if DB == nil {
-		log.Fatal("Database connection has not been initialized")
+// dialectorFor selects the GORM dialector of the configured driver
+// It also returns a description of the database location for logging.
+func dialectorFor(cfg *config.Config) (gorm.Dialector, string, error) {
+	switch cfg.Database.Driver {
+	case config.DriverPostgres, "":
+		location := cfg.Database.Host + ":" + cfg.Database.Port
+		if cfg.Database.URL != "" {
+			location = "DATABASE_URL"
+		}
+		return postgres.Open(cfg.GetDatabaseURL()), location, nil
+	case config.DriverSQLite:
+		path := cfg.Database.Path
+		if path == "" {
+			path = config.SQLiteMemory
+		}
+		// Wait for locks instead of failing and enforce foreign keys like PostgreSQL
+		dsn := path + "?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)"
+		return sqlite.Open(dsn), "sqlite " + path, nil
+	default:
+		return nil, "", fmt.Errorf("unsupported database driver %q (expected %q or %q)",
+			cfg.Database.Driver, config.DriverPostgres, config.DriverSQLite)
 	}
-	return DB
 }
 
 // InitializeData creates the initial data required for the league
-func InitializeData() error {
-	// First, clear existing data
-	if err := clearExistingData(); err != nil {
-		return fmt.Errorf("error clearing existing data: %v", err)
-	}
+func InitializeData(database *gorm.DB) error {
+	return ResetLeague(database.Statement.Context, NewSQLStore(database), SeedLeague)
+}
 
+// ResetLeague deletes the league in store, including its snapshots, and
+// creates a new one with seed in the same transaction
+func ResetLeague(ctx context.Context, store Store, seed func(ctx context.Context, store Store) error) error {
+	return store.Transaction(ctx, func(tx Store) error {
+		// First, clear existing data
+		if err := tx.ClearLeague(ctx); err != nil {
+			return fmt.Errorf("error clearing existing data: %v", err)
+		}
+		return seed(ctx, tx)
+	})
+}
+
+// SeedLeague creates the teams with their squads and statistics and the
+// fixture of the season in an empty store
+func SeedLeague(ctx context.Context, store Store) error {
 	// Define the teams
 	teams := []models.Team{
 		{Name: "Galatasaray", Attack: 80, Defense: 75},
@@ -57,47 +133,91 @@ func InitializeData() error {
 		{Name: "Trabzonspor", Attack: 50, Defense: 50},
 	}
 
-	// Create TeamStats for each team
-	for _, team := range teams {
-		if err := DB.Create(&team).Error; err != nil {
-			return fmt.Errorf("error creating team: %v", err)
+	return store.Transaction(ctx, func(tx Store) error {
+		// Create TeamStats and the squad for each team
+		for i := range teams {
+			team := &teams[i]
+			stats := models.TeamStats{
+				AvgScored:       float64(team.Attack) / 100.0,
+				AvgConceded:     float64(100-team.Defense) / 100.0,
+				AttackStrength:  float64(team.Attack) / 75.0,
+				DefenseStrength: float64(team.Defense) / 75.0,
+			}
+			if err := createTeam(ctx, tx, team, i, stats); err != nil {
+				return err
+			}
 		}
 
-		stats := models.TeamStats{
-			TeamID:          team.ID,
-			Played:          0,
-			Won:             0,
-			Drawn:           0,
-			Lost:            0,
-			GoalsFor:        0,
-			GoalsAway:       0,
-			Points:          0,
-			AvgScored:       float64(team.Attack) / 100.0,
-			AvgConceded:     float64(100-team.Defense) / 100.0,
-			AttackStrength:  float64(team.Attack) / 75.0,
-			DefenseStrength: float64(team.Defense) / 75.0,
-		}
-		if err := DB.Create(&stats).Error; err != nil {
-			return fmt.Errorf("error creating team stats: %v", err)
+		// Generate fixtures and store them
+		matches := generateFixtures(teams)
+		if err := tx.Matches().CreateMatches(ctx, matches); err != nil {
+			return fmt.Errorf("error creating match: %v", err)
 		}
-	}
+		return saveNewSeed(ctx, tx)
+	})
+}
 
-	// Fetch all teams from the DB
-	var allTeams []models.Team
-	if err := DB.Find(&allTeams).Error; err != nil {
-		return fmt.Errorf("error fetching teams: %v", err)
+// ImportLeague creates teams with generated squads and the given statistics,
+// and their fixture, in an empty store. stats[i] belongs to teams[i]. Matches
+// refer to their teams by HomeTeam.Name and AwayTeam.Name; their IDs are set.
+func ImportLeague(ctx context.Context, store Store, teams []models.Team, stats []models.TeamStats, matches []models.Match) error {
+	if len(stats) != len(teams) {
+		return fmt.Errorf("got statistics for %d of %d teams", len(stats), len(teams))
 	}
 
-	// Generate fixtures
-	matches := generateFixtures(allTeams)
+	return store.Transaction(ctx, func(tx Store) error {
+		ids := make(map[string]uint, len(teams))
+		for i := range teams {
+			if err := createTeam(ctx, tx, &teams[i], i, stats[i]); err != nil {
+				return err
+			}
+			ids[teams[i].Name] = teams[i].ID
+		}
 
-	// Insert matches into the DB
-	for _, match := range matches {
-		if err := DB.Create(&match).Error; err != nil {
-			return fmt.Errorf("error creating match: %v", err)
+		for i := range matches {
+			match := &matches[i]
+			homeID, homeOK := ids[match.HomeTeam.Name]
+			awayID, awayOK := ids[match.AwayTeam.Name]
+			if !homeOK || !awayOK {
+				return fmt.Errorf("%w: %q or %q", ErrTeamNotFound, match.HomeTeam.Name, match.AwayTeam.Name)
+			}
+			// Only the IDs are stored; the teams have been created above
+			match.HomeTeamID, match.AwayTeamID = homeID, awayID
+			match.HomeTeam, match.AwayTeam = models.Team{}, models.Team{}
 		}
+		if len(matches) > 0 {
+			if err := tx.Matches().CreateMatches(ctx, matches); err != nil {
+				return fmt.Errorf("error creating match: %v", err)
+			}
+		}
+		return saveNewSeed(ctx, tx)
+	})
+}
+
+// createTeam stores a team with its generated squad and its statistics
+// index selects the player names of the squad.
+func createTeam(ctx context.Context, tx Store, team *models.Team, index int, stats models.TeamStats) error {
+	if err := tx.Teams().CreateTeam(ctx, team); err != nil {
+		return fmt.Errorf("error creating team: %v", err)
+	}
+
+	squad := generateSquad(*team, index)
+	if err := tx.Teams().CreatePlayers(ctx, squad); err != nil {
+		return fmt.Errorf("error creating squad: %v", err)
+	}
+
+	stats.TeamID = team.ID
+	if err := tx.Teams().SaveStats(ctx, &stats); err != nil {
+		return fmt.Errorf("error creating team stats: %v", err)
 	}
+	return nil
+}
 
+// saveNewSeed chooses the random seed of a new league
+func saveNewSeed(ctx context.Context, tx Store) error {
+	if err := tx.Seeds().SaveSeed(ctx, NewLeagueSeed()); err != nil {
+		return fmt.Errorf("error creating league seed: %v", err)
+	}
 	return nil
 }
 
@@ -164,18 +284,34 @@ func generateFixtures(teams []models.Team) []models.Match {
 }
 
 // clearExistingData deletes all existing records in proper order
-func clearExistingData() error {
-	if err := DB.Exec("DELETE FROM predictions").Error; err != nil {
+func clearExistingData(database *gorm.DB) error {
+	// Snapshots refer to the old fixture and can no longer be restored
+	if err := database.Exec("DELETE FROM league_snapshots").Error; err != nil {
+		return fmt.Errorf("error deleting league_snapshots: %v", err)
+	}
+	if err := database.Exec("DELETE FROM player_absences").Error; err != nil {
+		return fmt.Errorf("error deleting player_absences: %v", err)
+	}
+	if err := database.Exec("DELETE FROM match_events").Error; err != nil {
+		return fmt.Errorf("error deleting match_events: %v", err)
+	}
+	if err := database.Exec("DELETE FROM predictions").Error; err != nil {
 		return fmt.Errorf("error deleting predictions: %v", err)
 	}
-	if err := DB.Exec("DELETE FROM team_stats").Error; err != nil {
+	if err := database.Exec("DELETE FROM players").Error; err != nil {
+		return fmt.Errorf("error deleting players: %v", err)
+	}
+	if err := database.Exec("DELETE FROM team_stats").Error; err != nil {
 		return fmt.Errorf("error deleting team_stats: %v", err)
 	}
-	if err := DB.Exec("DELETE FROM matches").Error; err != nil {
+	if err := database.Exec("DELETE FROM matches").Error; err != nil {
 		return fmt.Errorf("error deleting matches: %v", err)
 	}
-	if err := DB.Exec("DELETE FROM teams").Error; err != nil {
+	if err := database.Exec("DELETE FROM teams").Error; err != nil {
 		return fmt.Errorf("error deleting teams: %v", err)
 	}
+	if err := database.Exec("DELETE FROM league_seeds").Error; err != nil {
+		return fmt.Errorf("error deleting league_seeds: %v", err)
+	}
 	return nil
 }
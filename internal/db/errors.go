@@ -0,0 +1,30 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Record lookup errors
+var (
+	ErrTeamNotFound     = errors.New("team not found")
+	ErrMatchNotFound    = errors.New("match not found")
+	ErrPlayerNotFound   = errors.New("player not found")
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrJobNotFound      = errors.New("job not found")
+)
+
+// ErrSnapshotMismatch is returned when a snapshot was taken for a different fixture
+var ErrSnapshotMismatch = errors.New("snapshot does not belong to the current fixture")
+
+// notFound replaces gorm's record-not-found error with the given sentinel error
+// Other errors are returned unchanged
+func notFound(err error, sentinel error, id uint) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w (ID: %d)", sentinel, id)
+	}
+	return err
+}
@@ -0,0 +1,236 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+)
+
+// matchResultState is the stored result of a single match in a snapshot
+type matchResultState struct {
+	MatchID   uint      `json:"match_id"`
+	HomeGoals *uint     `json:"home_goals"`
+	AwayGoals *uint     `json:"away_goals"`
+	PlayedAt  time.Time `json:"played_at"`
+}
+
+// leagueState is the league progress stored in a snapshot
+type leagueState struct {
+	Matches     []matchResultState     `json:"matches"`
+	Events      []models.MatchEvent    `json:"events"`
+	Absences    []models.PlayerAbsence `json:"absences"`
+	Predictions []models.Prediction    `json:"predictions"`
+}
+
+// LastCompletedWeek returns the highest week with a played match, or 0 if none was played
+func LastCompletedWeek(ctx context.Context, store Store) (uint, error) {
+	played, err := store.Matches().ListMatches(ctx, MatchFilter{Played: Played(true)})
+	if err != nil {
+		return 0, err
+	}
+	if len(played) == 0 {
+		return 0, nil
+	}
+	return played[len(played)-1].Week, nil
+}
+
+// RewindToWeek clears all results after the given week together with their match
+// events, injuries/suspensions and predictions. A snapshot of the league is taken
+// first so the rewind can be undone. Week 0 rewinds to the start of the season.
+func RewindToWeek(ctx context.Context, store Store, week uint) (*models.LeagueSnapshot, error) {
+	var snapshot *models.LeagueSnapshot
+	err := store.WithSimulationLock(ctx, func(tx Store) error {
+		var err error
+		snapshot, err = createSnapshot(ctx, tx, fmt.Sprintf("Before rewind to week %d", week))
+		if err != nil {
+			return err
+		}
+
+		matches, err := tx.Matches().ListMatches(ctx, MatchFilter{})
+		if err != nil {
+			return fmt.Errorf("error fetching matches to rewind: %v", err)
+		}
+		for _, match := range matches {
+			if match.Week > week {
+				if err := clearResult(ctx, tx, match); err != nil {
+					return err
+				}
+			}
+		}
+		if err := tx.Predictions().DeletePredictionsFrom(ctx, week+1); err != nil {
+			return fmt.Errorf("error deleting predictions: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// CreateSnapshot saves the current league progress under the given label
+func CreateSnapshot(ctx context.Context, store Store, label string) (*models.LeagueSnapshot, error) {
+	var snapshot *models.LeagueSnapshot
+	err := store.WithSimulationLock(ctx, func(tx Store) error {
+		var err error
+		snapshot, err = createSnapshot(ctx, tx, label)
+		return err
+	})
+	return snapshot, err
+}
+
+// ListSnapshots returns the snapshot history, newest first
+func ListSnapshots(ctx context.Context, store Store) ([]models.LeagueSnapshot, error) {
+	return store.Snapshots().ListSnapshots(ctx)
+}
+
+// RestoreSnapshot replaces the league progress with the state stored in a snapshot
+// The current state is saved as a new snapshot first so the restore can be undone.
+// Snapshots taken before the fixture was regenerated cannot be restored.
+func RestoreSnapshot(ctx context.Context, store Store, snapshotID uint) (*models.LeagueSnapshot, error) {
+	var snapshot models.LeagueSnapshot
+	err := store.WithSimulationLock(ctx, func(tx Store) error {
+		var err error
+		snapshot, err = tx.Snapshots().FindSnapshot(ctx, snapshotID)
+		if err != nil {
+			return err
+		}
+
+		var state leagueState
+		if err := json.Unmarshal([]byte(snapshot.Data), &state); err != nil {
+			return fmt.Errorf("error decoding snapshot: %v", err)
+		}
+
+		matches, err := tx.Matches().ListMatches(ctx, MatchFilter{})
+		if err != nil {
+			return fmt.Errorf("error fetching matches: %v", err)
+		}
+		if !sameMatches(matches, state.Matches) {
+			return fmt.Errorf("%w (ID: %d)", ErrSnapshotMismatch, snapshotID)
+		}
+
+		if _, err := createSnapshot(ctx, tx, fmt.Sprintf("Before restoring snapshot %d", snapshotID)); err != nil {
+			return err
+		}
+
+		// Records are recreated with new IDs so that database sequences stay valid
+		events := make(map[uint][]models.MatchEvent)
+		for _, event := range state.Events {
+			event.ID = 0
+			events[event.MatchID] = append(events[event.MatchID], event)
+		}
+		absences := make(map[uint][]models.PlayerAbsence)
+		for _, absence := range state.Absences {
+			absence.ID = 0
+			absences[absence.MatchID] = append(absences[absence.MatchID], absence)
+		}
+		for _, result := range state.Matches {
+			match := models.Match{HomeGoals: result.HomeGoals, AwayGoals: result.AwayGoals, PlayedAt: result.PlayedAt}
+			match.ID = result.MatchID
+			if err := tx.Matches().SaveResult(ctx, &match); err != nil {
+				return fmt.Errorf("error restoring match result: %v", err)
+			}
+			if err := tx.Matches().ReplaceEvents(ctx, match.ID, events[match.ID]); err != nil {
+				return fmt.Errorf("error restoring match events: %v", err)
+			}
+			if err := tx.Matches().ReplaceAbsences(ctx, match.ID, absences[match.ID]); err != nil {
+				return fmt.Errorf("error restoring player absences: %v", err)
+			}
+		}
+
+		if err := tx.Predictions().DeletePredictionsFrom(ctx, 0); err != nil {
+			return fmt.Errorf("error deleting predictions: %v", err)
+		}
+		for i := range state.Predictions {
+			state.Predictions[i].ID = 0
+		}
+		if err := tx.Predictions().CreatePredictions(ctx, state.Predictions); err != nil {
+			return fmt.Errorf("error restoring predictions: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// createSnapshot stores the current league progress in store
+func createSnapshot(ctx context.Context, store Store, label string) (*models.LeagueSnapshot, error) {
+	league, err := store.ReadLeagueState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state := leagueState{
+		Matches:     make([]matchResultState, 0, len(league.Matches)),
+		Events:      league.Events,
+		Absences:    league.Absences,
+		Predictions: league.Predictions,
+	}
+	var week uint
+	for _, match := range league.Matches {
+		state.Matches = append(state.Matches, matchResultState{
+			MatchID:   match.ID,
+			HomeGoals: match.HomeGoals,
+			AwayGoals: match.AwayGoals,
+			PlayedAt:  match.PlayedAt,
+		})
+		if match.HomeGoals != nil && match.AwayGoals != nil {
+			week = max(week, match.Week)
+		}
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding snapshot: %v", err)
+	}
+
+	snapshot := models.LeagueSnapshot{
+		Label: label,
+		Week:  week,
+		Data:  string(data),
+	}
+	if err := store.Snapshots().CreateSnapshot(ctx, &snapshot); err != nil {
+		return nil, fmt.Errorf("error saving snapshot: %v", err)
+	}
+
+	return &snapshot, nil
+}
+
+// clearResult removes the result of a match and the records derived from it
+func clearResult(ctx context.Context, store Store, match models.Match) error {
+	match.HomeGoals, match.AwayGoals, match.PlayedAt = nil, nil, time.Time{}
+	if err := store.Matches().SaveResult(ctx, &match); err != nil {
+		return fmt.Errorf("error clearing match results: %v", err)
+	}
+	if err := store.Matches().ReplaceEvents(ctx, match.ID, nil); err != nil {
+		return fmt.Errorf("error deleting match events: %v", err)
+	}
+	if err := store.Matches().ReplaceAbsences(ctx, match.ID, nil); err != nil {
+		return fmt.Errorf("error deleting player absences: %v", err)
+	}
+	return nil
+}
+
+// sameMatches reports whether a snapshot covers exactly the given matches
+func sameMatches(matches []models.Match, results []matchResultState) bool {
+	if len(matches) != len(results) {
+		return false
+	}
+	ids := make(map[uint]bool, len(matches))
+	for _, match := range matches {
+		ids[match.ID] = true
+	}
+	for _, result := range results {
+		if !ids[result.MatchID] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,116 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LeagueState is the complete content of a league: the teams with their
+// statistics and squads, the fixture with its results and timelines, and the
+// injuries, suspensions and predictions, and the random seed its matches are
+// simulated with. Records keep their database IDs.
+type LeagueState struct {
+	Teams       []models.Team          // Without associations
+	Stats       []models.TeamStats     // One per team
+	Players     []models.Player        // Squads of all teams, removed players included
+	Matches     []models.Match         // Without associations
+	Events      []models.MatchEvent    // Timelines of the played matches
+	Absences    []models.PlayerAbsence // Injuries and suspensions
+	Predictions []models.Prediction    // Championship predictions of all weeks
+	Seed        *models.LeagueSeed     // nil if the league has no seed yet
+}
+
+// ReadLeagueState reads the complete league, every table ordered by ID
+// Removed players are read with their DeletedAt, since the timelines and
+// absences of the matches they played still refer to them.
+func (s *SQLStore) ReadLeagueState(ctx context.Context) (*LeagueState, error) {
+	state := &LeagueState{}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		reads := []struct {
+			table    string
+			dest     interface{}
+			unscoped bool
+		}{
+			{"teams", &state.Teams, false},
+			{"team_stats", &state.Stats, false},
+			{"players", &state.Players, true},
+			{"matches", &state.Matches, false},
+			{"match_events", &state.Events, false},
+			{"player_absences", &state.Absences, false},
+			{"predictions", &state.Predictions, false},
+		}
+		for _, read := range reads {
+			query := tx
+			if read.unscoped {
+				query = query.Unscoped()
+			}
+			if err := query.Order("id").Find(read.dest).Error; err != nil {
+				return fmt.Errorf("error fetching %s: %v", read.table, err)
+			}
+		}
+		seed, err := NewSQLStore(tx).Seeds().GetSeed(ctx)
+		if err != nil {
+			return fmt.Errorf("error fetching league_seeds: %v", err)
+		}
+		state.Seed = seed
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// ReplaceLeagueState deletes the league, including its snapshots, and stores
+// state with its original IDs. On PostgreSQL the ID sequences are moved past
+// the restored records so that new records do not collide with them.
+func (s *SQLStore) ReplaceLeagueState(ctx context.Context, state *LeagueState) error {
+	return WithSimulationLock(s.db.WithContext(ctx), func(tx *gorm.DB) error {
+		if err := clearExistingData(tx); err != nil {
+			return fmt.Errorf("error clearing existing data: %v", err)
+		}
+
+		// Tables in insert order, referenced records first
+		inserts := []struct {
+			table  string
+			count  int
+			record interface{}
+		}{
+			{"teams", len(state.Teams), &state.Teams},
+			{"team_stats", len(state.Stats), &state.Stats},
+			{"players", len(state.Players), &state.Players},
+			{"matches", len(state.Matches), &state.Matches},
+			{"match_events", len(state.Events), &state.Events},
+			{"player_absences", len(state.Absences), &state.Absences},
+			{"predictions", len(state.Predictions), &state.Predictions},
+		}
+		for _, insert := range inserts {
+			if insert.count == 0 {
+				continue
+			}
+			if err := tx.Omit(clause.Associations).Create(insert.record).Error; err != nil {
+				return fmt.Errorf("error restoring %s: %v", insert.table, err)
+			}
+		}
+		if state.Seed != nil {
+			if err := NewSQLStore(tx).Seeds().SaveSeed(ctx, state.Seed); err != nil {
+				return fmt.Errorf("error restoring league_seeds: %v", err)
+			}
+		}
+
+		if tx.Dialector.Name() == "postgres" {
+			for _, insert := range inserts {
+				if err := tx.Exec(fmt.Sprintf(
+					"SELECT setval(pg_get_serial_sequence('%[1]s', 'id'), COALESCE((SELECT MAX(id) FROM %[1]s), 0) + 1, false)",
+					insert.table)).Error; err != nil {
+					return fmt.Errorf("error resetting %s sequence: %v", insert.table, err)
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,33 @@
+package db
+
+import (
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// simulationLockKey identifies the PostgreSQL advisory lock that guards league progression
+const simulationLockKey int64 = 0x4c45414755450001
+
+// simulationMu serializes simulations started from the same process
+var simulationMu sync.Mutex
+
+// WithSimulationLock runs fn in a transaction that holds the league simulation lock
+// Within a process a mutex serializes callers; on PostgreSQL a transaction-scoped
+// advisory lock additionally serializes other server instances sharing the database.
+// The lock is released when the transaction commits or rolls back, so fn must not
+// call WithSimulationLock again.
+func WithSimulationLock(database *gorm.DB, fn func(tx *gorm.DB) error) error {
+	simulationMu.Lock()
+	defer simulationMu.Unlock()
+
+	return database.Transaction(func(tx *gorm.DB) error {
+		if tx.Dialector.Name() == "postgres" {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", simulationLockKey).Error; err != nil {
+				return fmt.Errorf("error acquiring simulation lock: %v", err)
+			}
+		}
+		return fn(tx)
+	})
+}
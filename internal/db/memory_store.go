@@ -0,0 +1,733 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+	"gorm.io/gorm"
+)
+
+// MemoryStore is a Store that keeps the league in memory
+// It needs no database, which makes it suitable for tests and offline use.
+// Transactions work on a copy of the league that replaces it on success;
+// writers are serialized, so a transaction never loses concurrent changes.
+type MemoryStore struct {
+	writeMu sync.Mutex   // Serializes transactions and writes
+	simMu   sync.Mutex   // Simulation lock
+	mu      sync.RWMutex // Guards state
+	state   *memoryState
+}
+
+// memoryState is the content of a MemoryStore
+type memoryState struct {
+	lastIDs     map[string]uint // Last ID handed out per table
+	teams       []models.Team
+	stats       []models.TeamStats
+	players     []models.Player
+	matches     []models.Match
+	events      []models.MatchEvent
+	absences    []models.PlayerAbsence
+	predictions []models.Prediction
+	snapshots   []models.LeagueSnapshot
+	seed        *models.LeagueSeed
+}
+
+// NewMemoryStore returns an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{state: &memoryState{lastIDs: make(map[string]uint)}}
+}
+
+// Teams returns the team repository of the store
+func (s *MemoryStore) Teams() TeamRepository { return memoryTeams{s} }
+
+// Matches returns the match repository of the store
+func (s *MemoryStore) Matches() MatchRepository { return memoryMatches{s} }
+
+// Predictions returns the prediction repository of the store
+func (s *MemoryStore) Predictions() PredictionRepository { return memoryPredictions{s} }
+
+// Seeds returns the seed repository of the store
+func (s *MemoryStore) Seeds() SeedRepository { return memorySeeds{s} }
+
+// Snapshots returns the snapshot repository of the store
+func (s *MemoryStore) Snapshots() SnapshotRepository { return memorySnapshots{s} }
+
+// ClearLeague deletes the league, including its snapshots and seed
+func (s *MemoryStore) ClearLeague(ctx context.Context) error {
+	return s.write(ctx, func(st *memoryState) error {
+		*st = memoryState{lastIDs: st.lastIDs}
+		return nil
+	})
+}
+
+// Transaction runs fn on a copy of the league that replaces it if fn returns nil
+func (s *MemoryStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.mu.RLock()
+	tx := &MemoryStore{state: s.state.clone()}
+	s.mu.RUnlock()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.state = tx.state
+	s.mu.Unlock()
+	return nil
+}
+
+// WithSimulationLock runs fn in a transaction while holding the simulation lock
+func (s *MemoryStore) WithSimulationLock(ctx context.Context, fn func(tx Store) error) error {
+	s.simMu.Lock()
+	defer s.simMu.Unlock()
+	return s.Transaction(ctx, fn)
+}
+
+// ReadLeagueState returns a copy of the complete league
+func (s *MemoryStore) ReadLeagueState(ctx context.Context) (*LeagueState, error) {
+	var state *LeagueState
+	err := s.read(ctx, func(st *memoryState) error {
+		copied := st.clone()
+		state = &LeagueState{
+			Teams:       copied.teams,
+			Stats:       copied.stats,
+			Players:     copied.players,
+			Matches:     copied.matches,
+			Events:      copied.events,
+			Absences:    copied.absences,
+			Predictions: copied.predictions,
+			Seed:        copied.seed,
+		}
+		return nil
+	})
+	return state, err
+}
+
+// ReplaceLeagueState replaces the league with a copy of state while holding
+// the simulation lock. New records get IDs after the largest restored ones.
+func (s *MemoryStore) ReplaceLeagueState(ctx context.Context, state *LeagueState) error {
+	s.simMu.Lock()
+	defer s.simMu.Unlock()
+
+	replaced := (&memoryState{
+		lastIDs:     make(map[string]uint),
+		teams:       state.Teams,
+		stats:       state.Stats,
+		players:     state.Players,
+		matches:     state.Matches,
+		events:      state.Events,
+		absences:    state.Absences,
+		predictions: state.Predictions,
+		seed:        state.Seed,
+	}).clone()
+	for _, team := range replaced.teams {
+		replaced.lastIDs["teams"] = max(replaced.lastIDs["teams"], team.ID)
+	}
+	for _, stats := range replaced.stats {
+		replaced.lastIDs["team_stats"] = max(replaced.lastIDs["team_stats"], stats.ID)
+	}
+	for i := range replaced.stats {
+		// Archived statistics are identified by their team and may come without an ID
+		if replaced.stats[i].ID == 0 {
+			replaced.stats[i].ID = replaced.nextID("team_stats")
+		}
+	}
+	for _, player := range replaced.players {
+		replaced.lastIDs["players"] = max(replaced.lastIDs["players"], player.ID)
+	}
+	for _, match := range replaced.matches {
+		replaced.lastIDs["matches"] = max(replaced.lastIDs["matches"], match.ID)
+	}
+	for _, event := range replaced.events {
+		replaced.lastIDs["match_events"] = max(replaced.lastIDs["match_events"], event.ID)
+	}
+	for _, absence := range replaced.absences {
+		replaced.lastIDs["player_absences"] = max(replaced.lastIDs["player_absences"], absence.ID)
+	}
+	for _, prediction := range replaced.predictions {
+		replaced.lastIDs["predictions"] = max(replaced.lastIDs["predictions"], prediction.ID)
+	}
+
+	return s.write(ctx, func(st *memoryState) error {
+		*st = *replaced
+		return nil
+	})
+}
+
+// read runs fn with the current state for reading
+func (s *MemoryStore) read(ctx context.Context, fn func(state *memoryState) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn(s.state)
+}
+
+// write runs fn with the current state for writing
+func (s *MemoryStore) write(ctx context.Context, fn func(state *memoryState) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(s.state)
+}
+
+// clone returns a copy of the state that can be changed independently
+func (st *memoryState) clone() *memoryState {
+	lastIDs := make(map[string]uint, len(st.lastIDs))
+	for table, id := range st.lastIDs {
+		lastIDs[table] = id
+	}
+	var seed *models.LeagueSeed
+	if st.seed != nil {
+		copied := *st.seed
+		seed = &copied
+	}
+	return &memoryState{
+		lastIDs:     lastIDs,
+		teams:       append([]models.Team(nil), st.teams...),
+		stats:       append([]models.TeamStats(nil), st.stats...),
+		players:     append([]models.Player(nil), st.players...),
+		matches:     append([]models.Match(nil), st.matches...),
+		events:      append([]models.MatchEvent(nil), st.events...),
+		absences:    append([]models.PlayerAbsence(nil), st.absences...),
+		predictions: append([]models.Prediction(nil), st.predictions...),
+		snapshots:   append([]models.LeagueSnapshot(nil), st.snapshots...),
+		seed:        seed,
+	}
+}
+
+// nextID hands out a new record ID of a table
+func (st *memoryState) nextID(table string) uint {
+	st.lastIDs[table]++
+	return st.lastIDs[table]
+}
+
+// memoryTeams implements TeamRepository on a MemoryStore
+type memoryTeams struct {
+	s *MemoryStore
+}
+
+func (r memoryTeams) ListTeams(ctx context.Context) ([]models.Team, error) {
+	var teams []models.Team
+	err := r.s.read(ctx, func(st *memoryState) error {
+		teams = append(teams, st.teams...)
+		return nil
+	})
+	return teams, err
+}
+
+func (r memoryTeams) CountTeams(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.s.read(ctx, func(st *memoryState) error {
+		count = int64(len(st.teams))
+		return nil
+	})
+	return count, err
+}
+
+func (r memoryTeams) FindTeam(ctx context.Context, id uint) (models.Team, error) {
+	var team models.Team
+	err := r.s.read(ctx, func(st *memoryState) error {
+		for _, t := range st.teams {
+			if t.ID == id {
+				team = t
+				return nil
+			}
+		}
+		return fmt.Errorf("%w (ID: %d)", ErrTeamNotFound, id)
+	})
+	return team, err
+}
+
+func (r memoryTeams) CreateTeam(ctx context.Context, team *models.Team) error {
+	return r.s.write(ctx, func(st *memoryState) error {
+		team.ID = st.nextID("teams")
+		team.CreatedAt = time.Now()
+		team.UpdatedAt = team.CreatedAt
+		st.teams = append(st.teams, *team)
+		return nil
+	})
+}
+
+func (r memoryTeams) GetStats(ctx context.Context, teamID uint) (models.TeamStats, error) {
+	var stats models.TeamStats
+	err := r.s.read(ctx, func(st *memoryState) error {
+		for _, s := range st.stats {
+			if s.TeamID == teamID {
+				stats = s
+				return nil
+			}
+		}
+		return ErrTeamNotFound
+	})
+	return stats, err
+}
+
+func (r memoryTeams) SaveStats(ctx context.Context, stats *models.TeamStats) error {
+	return r.s.write(ctx, func(st *memoryState) error {
+		stats.UpdatedAt = time.Now()
+		for i := range st.stats {
+			if st.stats[i].TeamID == stats.TeamID {
+				stats.ID = st.stats[i].ID
+				stats.CreatedAt = st.stats[i].CreatedAt
+				st.stats[i] = *stats
+				return nil
+			}
+		}
+		stats.ID = st.nextID("team_stats")
+		stats.CreatedAt = stats.UpdatedAt
+		st.stats = append(st.stats, *stats)
+		return nil
+	})
+}
+
+func (r memoryTeams) ListPlayers(ctx context.Context, teamID uint) ([]models.Player, error) {
+	var players []models.Player
+	err := r.s.read(ctx, func(st *memoryState) error {
+		for _, player := range st.players {
+			if player.TeamID == teamID && !player.DeletedAt.Valid {
+				players = append(players, player)
+			}
+		}
+		return nil
+	})
+	sort.SliceStable(players, func(i, j int) bool {
+		if players[i].ShirtNumber != players[j].ShirtNumber {
+			return players[i].ShirtNumber < players[j].ShirtNumber
+		}
+		return players[i].ID < players[j].ID
+	})
+	return players, err
+}
+
+func (r memoryTeams) CreatePlayers(ctx context.Context, players []models.Player) error {
+	return r.s.write(ctx, func(st *memoryState) error {
+		now := time.Now()
+		for i := range players {
+			players[i].ID = st.nextID("players")
+			players[i].CreatedAt = now
+			players[i].UpdatedAt = now
+			st.players = append(st.players, players[i])
+		}
+		return nil
+	})
+}
+
+func (r memoryTeams) FindPlayer(ctx context.Context, id uint) (models.Player, error) {
+	var player models.Player
+	err := r.s.read(ctx, func(st *memoryState) error {
+		for _, p := range st.players {
+			if p.ID == id && !p.DeletedAt.Valid {
+				player = p
+				return nil
+			}
+		}
+		return fmt.Errorf("%w (ID: %d)", ErrPlayerNotFound, id)
+	})
+	return player, err
+}
+
+func (r memoryTeams) FindPlayers(ctx context.Context, ids []uint) ([]models.Player, error) {
+	var players []models.Player
+	err := r.s.read(ctx, func(st *memoryState) error {
+		wanted := make(map[uint]bool, len(ids))
+		for _, id := range ids {
+			wanted[id] = true
+		}
+		for _, player := range st.players {
+			if wanted[player.ID] {
+				players = append(players, player)
+			}
+		}
+		return nil
+	})
+	return players, err
+}
+
+func (r memoryTeams) SavePlayer(ctx context.Context, player *models.Player) error {
+	return r.s.write(ctx, func(st *memoryState) error {
+		for i := range st.players {
+			if st.players[i].ID == player.ID && !st.players[i].DeletedAt.Valid {
+				player.CreatedAt = st.players[i].CreatedAt
+				player.UpdatedAt = time.Now()
+				st.players[i] = *player
+				return nil
+			}
+		}
+		return fmt.Errorf("%w (ID: %d)", ErrPlayerNotFound, player.ID)
+	})
+}
+
+func (r memoryTeams) DeletePlayer(ctx context.Context, id uint) error {
+	return r.s.write(ctx, func(st *memoryState) error {
+		for i := range st.players {
+			if st.players[i].ID == id && !st.players[i].DeletedAt.Valid {
+				// Removed players are kept, like soft-deleted rows, so that their events keep their names
+				st.players[i].DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+				return nil
+			}
+		}
+		return fmt.Errorf("%w (ID: %d)", ErrPlayerNotFound, id)
+	})
+}
+
+func (r memoryTeams) UnavailablePlayers(ctx context.Context, teamID uint) (map[uint]uint, error) {
+	remaining := make(map[uint]uint)
+	err := r.s.read(ctx, func(st *memoryState) error {
+		// Weeks in which the team has already played, used to count matches served
+		var playedWeeks []uint
+		for _, match := range st.matches {
+			if (match.HomeTeamID == teamID || match.AwayTeamID == teamID) && isPlayed(match) {
+				playedWeeks = append(playedWeeks, match.Week)
+			}
+		}
+
+		for _, absence := range st.absences {
+			if absence.TeamID != teamID {
+				continue
+			}
+			if matches := absence.RemainingMatches(playedWeeks); matches > remaining[absence.PlayerID] {
+				remaining[absence.PlayerID] = matches
+			}
+		}
+		return nil
+	})
+	return remaining, err
+}
+
+// memoryMatches implements MatchRepository on a MemoryStore
+type memoryMatches struct {
+	s *MemoryStore
+}
+
+func (r memoryMatches) FindMatch(ctx context.Context, id uint) (models.Match, error) {
+	var match models.Match
+	err := r.s.read(ctx, func(st *memoryState) error {
+		for _, m := range st.matches {
+			if m.ID == id {
+				match = m
+				return nil
+			}
+		}
+		return fmt.Errorf("%w (ID: %d)", ErrMatchNotFound, id)
+	})
+	return match, err
+}
+
+func (r memoryMatches) ListMatches(ctx context.Context, filter MatchFilter) ([]models.Match, error) {
+	var matches []models.Match
+	err := r.s.read(ctx, func(st *memoryState) error {
+		for _, match := range st.matches {
+			if filter.matches(match) {
+				matches = append(matches, match)
+			}
+		}
+		return nil
+	})
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Week != matches[j].Week {
+			return matches[i].Week < matches[j].Week
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	return matches, err
+}
+
+func (r memoryMatches) CountMatches(ctx context.Context, filter MatchFilter) (int64, error) {
+	var count int64
+	err := r.s.read(ctx, func(st *memoryState) error {
+		for _, match := range st.matches {
+			if filter.matches(match) {
+				count++
+			}
+		}
+		return nil
+	})
+	return count, err
+}
+
+// matches reports whether the filter selects match
+func (f MatchFilter) matches(match models.Match) bool {
+	if f.Week > 0 && match.Week != f.Week {
+		return false
+	}
+	if f.UpToWeek > 0 && match.Week > f.UpToWeek {
+		return false
+	}
+	if f.Played != nil && *f.Played != isPlayed(match) {
+		return false
+	}
+	return true
+}
+
+// isPlayed reports whether a match has a result
+func isPlayed(match models.Match) bool {
+	return match.HomeGoals != nil && match.AwayGoals != nil
+}
+
+func (r memoryMatches) CreateMatches(ctx context.Context, matches []models.Match) error {
+	return r.s.write(ctx, func(st *memoryState) error {
+		now := time.Now()
+		for i := range matches {
+			matches[i].ID = st.nextID("matches")
+			matches[i].CreatedAt = now
+			matches[i].UpdatedAt = now
+			st.matches = append(st.matches, matches[i])
+		}
+		return nil
+	})
+}
+
+func (r memoryMatches) SaveResult(ctx context.Context, match *models.Match) error {
+	return r.s.write(ctx, func(st *memoryState) error {
+		for i := range st.matches {
+			if st.matches[i].ID == match.ID {
+				st.matches[i].HomeGoals = match.HomeGoals
+				st.matches[i].AwayGoals = match.AwayGoals
+				st.matches[i].PlayedAt = match.PlayedAt
+				st.matches[i].UpdatedAt = time.Now()
+				return nil
+			}
+		}
+		return fmt.Errorf("%w (ID: %d)", ErrMatchNotFound, match.ID)
+	})
+}
+
+func (r memoryMatches) ListEvents(ctx context.Context, filter EventFilter) ([]models.MatchEvent, error) {
+	var events []models.MatchEvent
+	err := r.s.read(ctx, func(st *memoryState) error {
+		for _, event := range st.events {
+			if (filter.MatchID == 0 || event.MatchID == filter.MatchID) && (filter.Type == "" || event.Type == filter.Type) {
+				events = append(events, event)
+			}
+		}
+		return nil
+	})
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].MatchID != events[j].MatchID {
+			return events[i].MatchID < events[j].MatchID
+		}
+		if events[i].Minute != events[j].Minute {
+			return events[i].Minute < events[j].Minute
+		}
+		return events[i].ID < events[j].ID
+	})
+	return events, err
+}
+
+func (r memoryMatches) ReplaceEvents(ctx context.Context, matchID uint, events []models.MatchEvent) error {
+	return r.s.write(ctx, func(st *memoryState) error {
+		kept := st.events[:0:0]
+		for _, event := range st.events {
+			if event.MatchID != matchID {
+				kept = append(kept, event)
+			}
+		}
+		now := time.Now()
+		for i := range events {
+			events[i].ID = st.nextID("match_events")
+			events[i].MatchID = matchID
+			events[i].CreatedAt = now
+			kept = append(kept, events[i])
+		}
+		st.events = kept
+		return nil
+	})
+}
+
+func (r memoryMatches) CountPlayerEvents(ctx context.Context, playerID uint, eventType string) (int64, error) {
+	var count int64
+	err := r.s.read(ctx, func(st *memoryState) error {
+		for _, event := range st.events {
+			if event.PlayerID != nil && *event.PlayerID == playerID && event.Type == eventType {
+				count++
+			}
+		}
+		return nil
+	})
+	return count, err
+}
+
+func (r memoryMatches) ReplaceAbsences(ctx context.Context, matchID uint, absences []models.PlayerAbsence) error {
+	return r.s.write(ctx, func(st *memoryState) error {
+		kept := st.absences[:0:0]
+		for _, absence := range st.absences {
+			if absence.MatchID != matchID {
+				kept = append(kept, absence)
+			}
+		}
+		now := time.Now()
+		for i := range absences {
+			absences[i].ID = st.nextID("player_absences")
+			absences[i].MatchID = matchID
+			absences[i].CreatedAt = now
+			kept = append(kept, absences[i])
+		}
+		st.absences = kept
+		return nil
+	})
+}
+
+// memoryPredictions implements PredictionRepository on a MemoryStore
+type memoryPredictions struct {
+	s *MemoryStore
+}
+
+func (r memoryPredictions) SavePredictions(ctx context.Context, week uint, probabilities map[uint]float64) error {
+	return r.s.write(ctx, func(st *memoryState) error {
+		kept := st.predictions[:0:0]
+		for _, prediction := range st.predictions {
+			if prediction.Week != week {
+				kept = append(kept, prediction)
+			}
+		}
+		now := time.Now()
+		for teamID, probability := range probabilities {
+			kept = append(kept, models.Prediction{
+				ID:          st.nextID("predictions"),
+				Week:        week,
+				TeamID:      teamID,
+				Probability: probability,
+				CreatedAt:   now,
+			})
+		}
+		st.predictions = kept
+		return nil
+	})
+}
+
+func (r memoryPredictions) ListPredictions(ctx context.Context, week uint) ([]models.Prediction, error) {
+	var predictions []models.Prediction
+	err := r.s.read(ctx, func(st *memoryState) error {
+		for _, prediction := range st.predictions {
+			if prediction.Week == week {
+				predictions = append(predictions, prediction)
+			}
+		}
+		return nil
+	})
+	sort.SliceStable(predictions, func(i, j int) bool {
+		if predictions[i].Probability != predictions[j].Probability {
+			return predictions[i].Probability > predictions[j].Probability
+		}
+		return predictions[i].TeamID < predictions[j].TeamID
+	})
+	return predictions, err
+}
+
+func (r memoryPredictions) CreatePredictions(ctx context.Context, predictions []models.Prediction) error {
+	return r.s.write(ctx, func(st *memoryState) error {
+		for i := range predictions {
+			predictions[i].ID = st.nextID("predictions")
+			if predictions[i].CreatedAt.IsZero() {
+				predictions[i].CreatedAt = time.Now()
+			}
+			st.predictions = append(st.predictions, predictions[i])
+		}
+		return nil
+	})
+}
+
+func (r memoryPredictions) DeletePredictionsFrom(ctx context.Context, week uint) error {
+	return r.s.write(ctx, func(st *memoryState) error {
+		kept := st.predictions[:0:0]
+		for _, prediction := range st.predictions {
+			if prediction.Week < week {
+				kept = append(kept, prediction)
+			}
+		}
+		st.predictions = kept
+		return nil
+	})
+}
+
+// memorySeeds implements SeedRepository on a MemoryStore
+type memorySeeds struct {
+	s *MemoryStore
+}
+
+func (r memorySeeds) GetSeed(ctx context.Context) (*models.LeagueSeed, error) {
+	var seed *models.LeagueSeed
+	err := r.s.read(ctx, func(st *memoryState) error {
+		if st.seed != nil {
+			copied := *st.seed
+			seed = &copied
+		}
+		return nil
+	})
+	return seed, err
+}
+
+func (r memorySeeds) SaveSeed(ctx context.Context, seed *models.LeagueSeed) error {
+	return r.s.write(ctx, func(st *memoryState) error {
+		seed.ID = leagueSeedID
+		copied := *seed
+		st.seed = &copied
+		return nil
+	})
+}
+
+// memorySnapshots implements SnapshotRepository on a MemoryStore
+type memorySnapshots struct {
+	s *MemoryStore
+}
+
+func (r memorySnapshots) ListSnapshots(ctx context.Context) ([]models.LeagueSnapshot, error) {
+	var snapshots []models.LeagueSnapshot
+	err := r.s.read(ctx, func(st *memoryState) error {
+		for _, snapshot := range st.snapshots {
+			snapshot.Data = ""
+			snapshots = append(snapshots, snapshot)
+		}
+		return nil
+	})
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		if !snapshots[i].CreatedAt.Equal(snapshots[j].CreatedAt) {
+			return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
+		}
+		return snapshots[i].ID > snapshots[j].ID
+	})
+	return snapshots, err
+}
+
+func (r memorySnapshots) FindSnapshot(ctx context.Context, id uint) (models.LeagueSnapshot, error) {
+	var snapshot models.LeagueSnapshot
+	err := r.s.read(ctx, func(st *memoryState) error {
+		for _, s := range st.snapshots {
+			if s.ID == id {
+				snapshot = s
+				return nil
+			}
+		}
+		return fmt.Errorf("%w (ID: %d)", ErrSnapshotNotFound, id)
+	})
+	return snapshot, err
+}
+
+func (r memorySnapshots) CreateSnapshot(ctx context.Context, snapshot *models.LeagueSnapshot) error {
+	return r.s.write(ctx, func(st *memoryState) error {
+		snapshot.ID = st.nextID("league_snapshots")
+		snapshot.CreatedAt = time.Now()
+		st.snapshots = append(st.snapshots, *snapshot)
+		return nil
+	})
+}
@@ -0,0 +1,273 @@
+package db
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tarikbacak/insider-league-simulator/migrations"
+	"gorm.io/gorm"
+)
+
+// migrationLockKey identifies the PostgreSQL advisory lock that serializes
+// server instances migrating the same database
+const migrationLockKey int64 = 0x4c45414755450002
+
+// migrationFile matches migration file names such as 001_create_tables.up.sql
+var migrationFile = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a versioned change of the database schema
+type Migration struct {
+	Version uint   `json:"version"` // Order in which the migration is applied
+	Name    string `json:"name"`    // Description taken from the file name
+	Up      string `json:"-"`       // SQL applying the change
+	Down    string `json:"-"`       // SQL reverting the change
+}
+
+// MigrationStatus reports whether a migration has been applied
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time `json:"applied_at,omitempty"` // nil while the migration is pending
+}
+
+// schemaMigration is a row of the schema_migrations table
+type schemaMigration struct {
+	Version   uint      `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName overrides the table name used by schemaMigration
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrate applies the pending migrations of the database's driver in version order
+// Each migration runs in its own transaction together with its schema_migrations
+// row, so a failed migration leaves the schema at the previous version.
+func Migrate(database *gorm.DB) error {
+	list, err := LoadMigrations(database.Dialector.Name())
+	if err != nil {
+		return err
+	}
+	if err := ensureMigrationTable(database); err != nil {
+		return err
+	}
+
+	for _, migration := range list {
+		applied := false
+		err := database.Transaction(func(tx *gorm.DB) error {
+			if err := lockMigrations(tx); err != nil {
+				return err
+			}
+			// Another instance may have applied it while this one was waiting
+			var count int64
+			if err := tx.Model(&schemaMigration{}).Where("version = ?", migration.Version).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return nil
+			}
+			if err := execStatements(tx, migration.Up); err != nil {
+				return err
+			}
+			applied = true
+			return tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("error applying migration %03d_%s: %w", migration.Version, migration.Name, err)
+		}
+		if applied {
+			log.Printf("Applied migration %03d_%s", migration.Version, migration.Name)
+		}
+	}
+	return nil
+}
+
+// MigrateDown reverts the last steps applied migrations, newest first
+func MigrateDown(database *gorm.DB, steps int) error {
+	if steps < 1 {
+		return fmt.Errorf("invalid number of steps %d", steps)
+	}
+	list, err := LoadMigrations(database.Dialector.Name())
+	if err != nil {
+		return err
+	}
+	if err := ensureMigrationTable(database); err != nil {
+		return err
+	}
+
+	byVersion := make(map[uint]Migration, len(list))
+	for _, migration := range list {
+		byVersion[migration.Version] = migration
+	}
+
+	for i := 0; i < steps; i++ {
+		reverted := false
+		var migration Migration
+		err := database.Transaction(func(tx *gorm.DB) error {
+			if err := lockMigrations(tx); err != nil {
+				return err
+			}
+			var rows []schemaMigration
+			if err := tx.Order("version DESC").Limit(1).Find(&rows).Error; err != nil {
+				return err
+			}
+			if len(rows) == 0 {
+				return nil
+			}
+			last := rows[0]
+			var ok bool
+			if migration, ok = byVersion[last.Version]; !ok {
+				return fmt.Errorf("applied migration %03d_%s has no migration file", last.Version, last.Name)
+			}
+			if err := execStatements(tx, migration.Down); err != nil {
+				return err
+			}
+			reverted = true
+			return tx.Delete(&schemaMigration{}, "version = ?", last.Version).Error
+		})
+		if err != nil {
+			return fmt.Errorf("error reverting migration: %w", err)
+		}
+		if !reverted {
+			break // Nothing left to revert
+		}
+		log.Printf("Reverted migration %03d_%s", migration.Version, migration.Name)
+	}
+	return nil
+}
+
+// GetMigrationStatus lists the migrations of the database's driver with the
+// time each applied one was applied
+func GetMigrationStatus(database *gorm.DB) ([]MigrationStatus, error) {
+	list, err := LoadMigrations(database.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureMigrationTable(database); err != nil {
+		return nil, err
+	}
+
+	var applied []schemaMigration
+	if err := database.Find(&applied).Error; err != nil {
+		return nil, err
+	}
+	appliedAt := make(map[uint]time.Time, len(applied))
+	for _, row := range applied {
+		appliedAt[row.Version] = row.AppliedAt
+	}
+
+	statuses := make([]MigrationStatus, 0, len(list))
+	for _, migration := range list {
+		status := MigrationStatus{Migration: migration}
+		if at, ok := appliedAt[migration.Version]; ok {
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// LoadMigrations reads the embedded migrations of a driver, sorted by version
+// Every version needs both an up and a down file.
+func LoadMigrations(driver string) ([]Migration, error) {
+	files, err := fs.ReadDir(migrations.Files, driver)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for database driver %q", driver)
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, file := range files {
+		match := migrationFile.FindStringSubmatch(file.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", file.Name())
+		}
+		version, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("invalid migration version in %q", file.Name())
+		}
+		content, err := fs.ReadFile(migrations.Files, driver+"/"+file.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[uint(version)]
+		if !ok {
+			migration = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by %q and %q", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	list := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %03d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		list = append(list, *migration)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+// ensureMigrationTable creates the schema_migrations table if it does not exist
+func ensureMigrationTable(database *gorm.DB) error {
+	err := database.Exec("CREATE TABLE IF NOT EXISTS schema_migrations (" +
+		"version BIGINT PRIMARY KEY, name TEXT NOT NULL, applied_at TIMESTAMP NOT NULL)").Error
+	if err != nil {
+		return fmt.Errorf("error creating schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+// lockMigrations serializes migrations of server instances sharing a PostgreSQL
+// database until the transaction ends; SQLite already allows a single writer
+func lockMigrations(tx *gorm.DB) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockKey).Error; err != nil {
+		return fmt.Errorf("error acquiring migration lock: %w", err)
+	}
+	return nil
+}
+
+// execStatements runs the semicolon separated statements of a migration file
+// Comment lines are skipped; a statement ends with a line ending in a semicolon.
+func execStatements(tx *gorm.DB, script string) error {
+	var statement strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		code := line
+		if i := strings.Index(code, "--"); i >= 0 {
+			code = code[:i]
+		}
+		code = strings.TrimSpace(code)
+		if code == "" {
+			continue
+		}
+		statement.WriteString(code)
+		statement.WriteString("\n")
+		if strings.HasSuffix(code, ";") {
+			if err := tx.Exec(statement.String()).Error; err != nil {
+				return err
+			}
+			statement.Reset()
+		}
+	}
+	if strings.TrimSpace(statement.String()) != "" {
+		return tx.Exec(statement.String()).Error
+	}
+	return nil
+}
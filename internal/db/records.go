@@ -0,0 +1,43 @@
+package db
+
+import (
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+	"gorm.io/gorm"
+)
+
+// FindTeam loads a team by ID, returning ErrTeamNotFound if it does not exist
+// Preloads applied to database are honoured.
+func FindTeam(database *gorm.DB, id uint) (models.Team, error) {
+	var team models.Team
+	err := database.First(&team, id).Error
+	return team, notFound(err, ErrTeamNotFound, id)
+}
+
+// FindMatch loads a match by ID, returning ErrMatchNotFound if it does not exist
+// Preloads applied to database are honoured.
+func FindMatch(database *gorm.DB, id uint) (models.Match, error) {
+	var match models.Match
+	err := database.First(&match, id).Error
+	return match, notFound(err, ErrMatchNotFound, id)
+}
+
+// FindPlayer loads a player by ID, returning ErrPlayerNotFound if it does not exist
+func FindPlayer(database *gorm.DB, id uint) (models.Player, error) {
+	var player models.Player
+	err := database.First(&player, id).Error
+	return player, notFound(err, ErrPlayerNotFound, id)
+}
+
+// FindWebhook loads a webhook by ID, returning ErrWebhookNotFound if it does not exist
+func FindWebhook(database *gorm.DB, id uint) (models.Webhook, error) {
+	var webhook models.Webhook
+	err := database.First(&webhook, id).Error
+	return webhook, notFound(err, ErrWebhookNotFound, id)
+}
+
+// FindJob loads a background job by ID, returning ErrJobNotFound if it does not exist
+func FindJob(database *gorm.DB, id uint) (models.Job, error) {
+	var job models.Job
+	err := database.First(&job, id).Error
+	return job, notFound(err, ErrJobNotFound, id)
+}
@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+)
+
+// TeamRepository stores the teams of the league with their statistics and squads
+type TeamRepository interface {
+	// ListTeams returns all teams ordered by ID
+	ListTeams(ctx context.Context) ([]models.Team, error)
+	// CountTeams returns the number of teams
+	CountTeams(ctx context.Context) (int64, error)
+	// FindTeam returns a team, or ErrTeamNotFound
+	FindTeam(ctx context.Context, id uint) (models.Team, error)
+	// CreateTeam stores a new team and sets its ID
+	CreateTeam(ctx context.Context, team *models.Team) error
+	// GetStats returns the statistics of a team, or ErrTeamNotFound
+	GetStats(ctx context.Context, teamID uint) (models.TeamStats, error)
+	// SaveStats creates or updates the statistics of a team
+	SaveStats(ctx context.Context, stats *models.TeamStats) error
+	// ListPlayers returns the squad of a team ordered by shirt number
+	ListPlayers(ctx context.Context, teamID uint) ([]models.Player, error)
+	// CreatePlayers stores new players and sets their IDs
+	CreatePlayers(ctx context.Context, players []models.Player) error
+	// FindPlayer returns a squad member, or ErrPlayerNotFound
+	FindPlayer(ctx context.Context, id uint) (models.Player, error)
+	// FindPlayers returns the players with the given IDs, including removed ones
+	FindPlayers(ctx context.Context, ids []uint) ([]models.Player, error)
+	// SavePlayer updates the details of a player
+	SavePlayer(ctx context.Context, player *models.Player) error
+	// DeletePlayer removes a player from their squad; their events are kept
+	DeletePlayer(ctx context.Context, id uint) error
+	// UnavailablePlayers returns the injured or suspended players of a team,
+	// mapped to the number of team matches they will still miss
+	UnavailablePlayers(ctx context.Context, teamID uint) (map[uint]uint, error)
+}
+
+// MatchFilter selects the matches returned by a MatchRepository
+// Zero values do not restrict the selection.
+type MatchFilter struct {
+	Week     uint  // Only matches of this week
+	UpToWeek uint  // Only matches up to and including this week
+	Played   *bool // Only played (true) or unplayed (false) matches
+}
+
+// Played returns a pointer to played, for use in MatchFilter
+func Played(played bool) *bool {
+	return &played
+}
+
+// EventFilter selects the match events returned by a MatchRepository
+// Zero values do not restrict the selection.
+type EventFilter struct {
+	MatchID uint   // Only events of this match
+	Type    string // Only events of this type
+}
+
+// MatchRepository stores the fixtures with their results, timelines and the
+// absences they caused
+type MatchRepository interface {
+	// FindMatch returns a match, or ErrMatchNotFound
+	FindMatch(ctx context.Context, id uint) (models.Match, error)
+	// ListMatches returns the matches selected by filter ordered by week and ID
+	ListMatches(ctx context.Context, filter MatchFilter) ([]models.Match, error)
+	// CountMatches returns the number of matches selected by filter
+	CountMatches(ctx context.Context, filter MatchFilter) (int64, error)
+	// CreateMatches stores new matches and sets their IDs
+	CreateMatches(ctx context.Context, matches []models.Match) error
+	// SaveResult stores the score and the time a match was played
+	SaveResult(ctx context.Context, match *models.Match) error
+	// ListEvents returns the events selected by filter ordered by match, minute and ID
+	ListEvents(ctx context.Context, filter EventFilter) ([]models.MatchEvent, error)
+	// ReplaceEvents replaces the timeline of a match
+	ReplaceEvents(ctx context.Context, matchID uint, events []models.MatchEvent) error
+	// CountPlayerEvents returns how many events of a type a player has been involved in
+	CountPlayerEvents(ctx context.Context, playerID uint, eventType string) (int64, error)
+	// ReplaceAbsences replaces the injuries and suspensions caused by a match
+	ReplaceAbsences(ctx context.Context, matchID uint, absences []models.PlayerAbsence) error
+}
+
+// PredictionRepository stores the championship predictions of each week
+type PredictionRepository interface {
+	// SavePredictions replaces the predictions of a week with probabilities keyed by team ID
+	SavePredictions(ctx context.Context, week uint, probabilities map[uint]float64) error
+	// ListPredictions returns the predictions of a week, most likely champion first
+	ListPredictions(ctx context.Context, week uint) ([]models.Prediction, error)
+	// CreatePredictions stores predictions as they are and sets their IDs
+	CreatePredictions(ctx context.Context, predictions []models.Prediction) error
+	// DeletePredictionsFrom deletes the predictions of the given week and later weeks
+	DeletePredictionsFrom(ctx context.Context, week uint) error
+}
+
+// SnapshotRepository stores the saved copies of the league progress
+type SnapshotRepository interface {
+	// ListSnapshots returns the snapshots without their data, newest first
+	ListSnapshots(ctx context.Context) ([]models.LeagueSnapshot, error)
+	// FindSnapshot returns a snapshot with its data, or ErrSnapshotNotFound
+	FindSnapshot(ctx context.Context, id uint) (models.LeagueSnapshot, error)
+	// CreateSnapshot stores a new snapshot and sets its ID and creation time
+	CreateSnapshot(ctx context.Context, snapshot *models.LeagueSnapshot) error
+}
+
+// SeedRepository stores the random seed that the matches of the league are simulated with
+type SeedRepository interface {
+	// GetSeed returns the seed of the league, or nil if none has been chosen yet
+	GetSeed(ctx context.Context) (*models.LeagueSeed, error)
+	// SaveSeed creates or replaces the seed of the league
+	SaveSeed(ctx context.Context, seed *models.LeagueSeed) error
+}
+
+// Store gives access to the repositories of a league
+// The simulator, the predictor and the league handlers only depend on Store,
+// so they run the same against the database (NewSQLStore) and in memory
+// (NewMemoryStore).
+type Store interface {
+	Teams() TeamRepository
+	Matches() MatchRepository
+	Predictions() PredictionRepository
+	Seeds() SeedRepository
+	Snapshots() SnapshotRepository
+
+	// ClearLeague deletes the league, including its snapshots and seed
+	ClearLeague(ctx context.Context) error
+	// ReadLeagueState returns the complete league, every table ordered by ID
+	ReadLeagueState(ctx context.Context) (*LeagueState, error)
+	// ReplaceLeagueState deletes the league, including its snapshots, and
+	// stores state with its original IDs while holding the simulation lock
+	ReplaceLeagueState(ctx context.Context, state *LeagueState) error
+
+	// Transaction runs fn with a store whose changes are kept only if fn returns nil
+	// Transactions may be nested; fn must use tx instead of the outer store.
+	Transaction(ctx context.Context, fn func(tx Store) error) error
+	// WithSimulationLock runs fn in a transaction that holds the league
+	// simulation lock, like the package-level WithSimulationLock
+	WithSimulationLock(ctx context.Context, fn func(tx Store) error) error
+}
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+)
+
+// leagueSeedID is the ID of the single row of the league_seeds table
+const leagueSeedID = 1
+
+// matchSeedStep separates the seeds of consecutive matches (2^64 / golden ratio)
+const matchSeedStep uint64 = 0x9E3779B97F4A7C15
+
+// NewLeagueSeed returns a randomly chosen seed for a new league
+func NewLeagueSeed() *models.LeagueSeed {
+	return &models.LeagueSeed{Seed: time.Now().UnixNano() + int64(rand.Intn(1000000))}
+}
+
+// NextMatchSeed returns the seed to simulate the next match of the league with
+// and counts the match. A league without a seed, such as one created before
+// seeds were stored, gets a random seed first.
+func NextMatchSeed(ctx context.Context, store Store) (int64, error) {
+	seed, err := store.Seeds().GetSeed(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error fetching league seed: %v", err)
+	}
+	if seed == nil {
+		seed = NewLeagueSeed()
+	}
+
+	matchSeed := int64(uint64(seed.Seed) + seed.Matches*matchSeedStep)
+	seed.Matches++
+	if err := store.Seeds().SaveSeed(ctx, seed); err != nil {
+		return 0, fmt.Errorf("error saving league seed: %v", err)
+	}
+	return matchSeed, nil
+}
@@ -0,0 +1,318 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+	"gorm.io/gorm"
+)
+
+// SQLStore is the Store backed by the PostgreSQL database through GORM
+type SQLStore struct {
+	db *gorm.DB
+}
+
+// NewSQLStore returns a Store that reads and writes the league in database
+func NewSQLStore(database *gorm.DB) *SQLStore {
+	return &SQLStore{db: database}
+}
+
+// Teams returns the team repository of the store
+func (s *SQLStore) Teams() TeamRepository { return sqlTeams{s.db} }
+
+// Matches returns the match repository of the store
+func (s *SQLStore) Matches() MatchRepository { return sqlMatches{s.db} }
+
+// Predictions returns the prediction repository of the store
+func (s *SQLStore) Predictions() PredictionRepository { return sqlPredictions{s.db} }
+
+// Seeds returns the seed repository of the store
+func (s *SQLStore) Seeds() SeedRepository { return sqlSeeds{s.db} }
+
+// Snapshots returns the snapshot repository of the store
+func (s *SQLStore) Snapshots() SnapshotRepository { return sqlSnapshots{s.db} }
+
+// ClearLeague deletes the league, including its snapshots and seed
+func (s *SQLStore) ClearLeague(ctx context.Context) error {
+	return clearExistingData(s.db.WithContext(ctx))
+}
+
+// Transaction runs fn in a database transaction (a savepoint when nested)
+func (s *SQLStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewSQLStore(tx))
+	})
+}
+
+// WithSimulationLock runs fn in a transaction that holds the league simulation lock
+func (s *SQLStore) WithSimulationLock(ctx context.Context, fn func(tx Store) error) error {
+	return WithSimulationLock(s.db.WithContext(ctx), func(tx *gorm.DB) error {
+		return fn(NewSQLStore(tx))
+	})
+}
+
+// sqlTeams implements TeamRepository on the teams, team_stats, players and player_absences tables
+type sqlTeams struct {
+	db *gorm.DB
+}
+
+func (r sqlTeams) ListTeams(ctx context.Context) ([]models.Team, error) {
+	var teams []models.Team
+	err := r.db.WithContext(ctx).Order("id").Find(&teams).Error
+	return teams, err
+}
+
+func (r sqlTeams) CountTeams(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Team{}).Count(&count).Error
+	return count, err
+}
+
+func (r sqlTeams) FindTeam(ctx context.Context, id uint) (models.Team, error) {
+	return FindTeam(r.db.WithContext(ctx), id)
+}
+
+func (r sqlTeams) CreateTeam(ctx context.Context, team *models.Team) error {
+	return r.db.WithContext(ctx).Create(team).Error
+}
+
+func (r sqlTeams) GetStats(ctx context.Context, teamID uint) (models.TeamStats, error) {
+	var stats models.TeamStats
+	err := r.db.WithContext(ctx).Where("team_id = ?", teamID).First(&stats).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return stats, ErrTeamNotFound
+	}
+	return stats, err
+}
+
+func (r sqlTeams) SaveStats(ctx context.Context, stats *models.TeamStats) error {
+	return r.db.WithContext(ctx).Save(stats).Error
+}
+
+func (r sqlTeams) ListPlayers(ctx context.Context, teamID uint) ([]models.Player, error) {
+	var players []models.Player
+	err := r.db.WithContext(ctx).Where("team_id = ?", teamID).Order("shirt_number, id").Find(&players).Error
+	return players, err
+}
+
+func (r sqlTeams) CreatePlayers(ctx context.Context, players []models.Player) error {
+	if len(players) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&players).Error
+}
+
+func (r sqlTeams) FindPlayer(ctx context.Context, id uint) (models.Player, error) {
+	return FindPlayer(r.db.WithContext(ctx), id)
+}
+
+func (r sqlTeams) FindPlayers(ctx context.Context, ids []uint) ([]models.Player, error) {
+	var players []models.Player
+	if len(ids) == 0 {
+		return players, nil
+	}
+	err := r.db.WithContext(ctx).Unscoped().Where("id IN ?", ids).Order("id").Find(&players).Error
+	return players, err
+}
+
+func (r sqlTeams) SavePlayer(ctx context.Context, player *models.Player) error {
+	return r.db.WithContext(ctx).Save(player).Error
+}
+
+func (r sqlTeams) DeletePlayer(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&models.Player{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w (ID: %d)", ErrPlayerNotFound, id)
+	}
+	return nil
+}
+
+func (r sqlTeams) UnavailablePlayers(ctx context.Context, teamID uint) (map[uint]uint, error) {
+	return GetUnavailablePlayers(r.db.WithContext(ctx), teamID)
+}
+
+// sqlMatches implements MatchRepository on the matches, match_events and player_absences tables
+type sqlMatches struct {
+	db *gorm.DB
+}
+
+func (r sqlMatches) FindMatch(ctx context.Context, id uint) (models.Match, error) {
+	return FindMatch(r.db.WithContext(ctx), id)
+}
+
+func (r sqlMatches) ListMatches(ctx context.Context, filter MatchFilter) ([]models.Match, error) {
+	var matches []models.Match
+	err := r.filter(ctx, filter).Order("week, id").Find(&matches).Error
+	return matches, err
+}
+
+func (r sqlMatches) CountMatches(ctx context.Context, filter MatchFilter) (int64, error) {
+	var count int64
+	err := r.filter(ctx, filter).Count(&count).Error
+	return count, err
+}
+
+// filter returns a query on the matches selected by filter
+func (r sqlMatches) filter(ctx context.Context, filter MatchFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.Match{})
+	if filter.Week > 0 {
+		query = query.Where("week = ?", filter.Week)
+	}
+	if filter.UpToWeek > 0 {
+		query = query.Where("week <= ?", filter.UpToWeek)
+	}
+	if filter.Played != nil {
+		if *filter.Played {
+			query = query.Where("home_goals IS NOT NULL AND away_goals IS NOT NULL")
+		} else {
+			query = query.Where("home_goals IS NULL AND away_goals IS NULL")
+		}
+	}
+	return query
+}
+
+func (r sqlMatches) CreateMatches(ctx context.Context, matches []models.Match) error {
+	if len(matches) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&matches).Error
+}
+
+func (r sqlMatches) SaveResult(ctx context.Context, match *models.Match) error {
+	return r.db.WithContext(ctx).Model(match).Select("home_goals", "away_goals", "played_at").Updates(match).Error
+}
+
+func (r sqlMatches) ListEvents(ctx context.Context, filter EventFilter) ([]models.MatchEvent, error) {
+	query := r.db.WithContext(ctx)
+	if filter.MatchID > 0 {
+		query = query.Where("match_id = ?", filter.MatchID)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	var events []models.MatchEvent
+	err := query.Order("match_id, minute, id").Find(&events).Error
+	return events, err
+}
+
+func (r sqlMatches) ReplaceEvents(ctx context.Context, matchID uint, events []models.MatchEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("match_id = ?", matchID).Delete(&models.MatchEvent{}).Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+		return tx.Create(&events).Error
+	})
+}
+
+func (r sqlMatches) CountPlayerEvents(ctx context.Context, playerID uint, eventType string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.MatchEvent{}).
+		Where("player_id = ? AND type = ?", playerID, eventType).
+		Count(&count).Error
+	return count, err
+}
+
+func (r sqlMatches) ReplaceAbsences(ctx context.Context, matchID uint, absences []models.PlayerAbsence) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("match_id = ?", matchID).Delete(&models.PlayerAbsence{}).Error; err != nil {
+			return err
+		}
+		if len(absences) == 0 {
+			return nil
+		}
+		return tx.Create(&absences).Error
+	})
+}
+
+// sqlPredictions implements PredictionRepository on the predictions table
+type sqlPredictions struct {
+	db *gorm.DB
+}
+
+func (r sqlPredictions) SavePredictions(ctx context.Context, week uint, probabilities map[uint]float64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.Prediction{}, "week = ?", week).Error; err != nil {
+			return err
+		}
+		for teamID, probability := range probabilities {
+			prediction := models.Prediction{
+				Week:        week,
+				TeamID:      teamID,
+				Probability: probability,
+			}
+			if err := tx.Create(&prediction).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r sqlPredictions) ListPredictions(ctx context.Context, week uint) ([]models.Prediction, error) {
+	var predictions []models.Prediction
+	err := r.db.WithContext(ctx).Where("week = ?", week).
+		Order("probability DESC, team_id").
+		Find(&predictions).Error
+	return predictions, err
+}
+
+func (r sqlPredictions) CreatePredictions(ctx context.Context, predictions []models.Prediction) error {
+	if len(predictions) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Omit("Team").Create(&predictions).Error
+}
+
+func (r sqlPredictions) DeletePredictionsFrom(ctx context.Context, week uint) error {
+	return r.db.WithContext(ctx).Where("week >= ?", week).Delete(&models.Prediction{}).Error
+}
+
+// sqlSnapshots implements SnapshotRepository on the league_snapshots table
+type sqlSnapshots struct {
+	db *gorm.DB
+}
+
+func (r sqlSnapshots) ListSnapshots(ctx context.Context) ([]models.LeagueSnapshot, error) {
+	var snapshots []models.LeagueSnapshot
+	err := r.db.WithContext(ctx).Omit("data").Order("created_at DESC, id DESC").Find(&snapshots).Error
+	return snapshots, err
+}
+
+func (r sqlSnapshots) FindSnapshot(ctx context.Context, id uint) (models.LeagueSnapshot, error) {
+	var snapshot models.LeagueSnapshot
+	err := r.db.WithContext(ctx).First(&snapshot, id).Error
+	return snapshot, notFound(err, ErrSnapshotNotFound, id)
+}
+
+func (r sqlSnapshots) CreateSnapshot(ctx context.Context, snapshot *models.LeagueSnapshot) error {
+	return r.db.WithContext(ctx).Create(snapshot).Error
+}
+
+// sqlSeeds implements SeedRepository on the league_seeds table
+type sqlSeeds struct {
+	db *gorm.DB
+}
+
+func (r sqlSeeds) GetSeed(ctx context.Context) (*models.LeagueSeed, error) {
+	var seeds []models.LeagueSeed
+	if err := r.db.WithContext(ctx).Where("id = ?", leagueSeedID).Limit(1).Find(&seeds).Error; err != nil {
+		return nil, err
+	}
+	if len(seeds) == 0 {
+		return nil, nil
+	}
+	return &seeds[0], nil
+}
+
+func (r sqlSeeds) SaveSeed(ctx context.Context, seed *models.LeagueSeed) error {
+	seed.ID = leagueSeedID
+	return r.db.WithContext(ctx).Save(seed).Error
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+)
+
+// squadTemplate describes the default squad composition: position, shirt number,
+// rating offset from the team's base rating and share of the team's goals
+var squadTemplate = []struct {
+	Position     string
+	ShirtNumber  uint
+	RatingOffset int
+	ScoringShare float64
+}{
+	{models.PositionGoalkeeper, 1, 0, 0},
+	{models.PositionGoalkeeper, 12, -8, 0},
+	{models.PositionDefender, 2, 2, 0.02},
+	{models.PositionDefender, 3, 0, 0.02},
+	{models.PositionDefender, 4, 1, 0.04},
+	{models.PositionDefender, 5, -2, 0.03},
+	{models.PositionDefender, 13, -6, 0.01},
+	{models.PositionMidfielder, 6, 1, 0.05},
+	{models.PositionMidfielder, 8, 3, 0.08},
+	{models.PositionMidfielder, 10, 5, 0.12},
+	{models.PositionMidfielder, 14, -3, 0.04},
+	{models.PositionMidfielder, 16, -5, 0.03},
+	{models.PositionForward, 7, 4, 0.14},
+	{models.PositionForward, 9, 6, 0.25},
+	{models.PositionForward, 11, 2, 0.12},
+	{models.PositionForward, 19, -4, 0.05},
+}
+
+// Name pools used to generate placeholder player names
+var (
+	firstNames = []string{"Ahmet", "Mehmet", "Emre", "Burak", "Cenk", "Arda", "Kerem", "Hakan", "Ozan", "Caner", "Mert", "Selçuk", "Volkan", "Umut", "Yusuf", "Barış", "Serdar", "Tolga", "Kaan", "Eren"}
+	lastNames  = []string{"Yılmaz", "Kaya", "Demir", "Şahin", "Çelik", "Yıldız", "Aydın", "Öztürk", "Arslan", "Doğan", "Kılıç", "Aslan", "Koç", "Kurt", "Özdemir", "Polat", "Erdem", "Güneş", "Aksoy", "Tekin", "Bulut"}
+)
+
+// generateSquad creates the default squad of a team
+// Attacking players are rated around the team's Attack value, goalkeepers and defenders around its Defense value
+func generateSquad(team models.Team, teamIndex int) []models.Player {
+	players := make([]models.Player, 0, len(squadTemplate))
+
+	for i, slot := range squadTemplate {
+		baseRating := team.Attack
+		if slot.Position == models.PositionGoalkeeper || slot.Position == models.PositionDefender {
+			baseRating = team.Defense
+		}
+
+		n := teamIndex*len(squadTemplate) + i
+		players = append(players, models.Player{
+			TeamID:       team.ID,
+			Name:         fmt.Sprintf("%s %s", firstNames[n%len(firstNames)], lastNames[(n*13)%len(lastNames)]),
+			ShirtNumber:  slot.ShirtNumber,
+			Position:     slot.Position,
+			Rating:       clampRating(baseRating + slot.RatingOffset),
+			ScoringShare: slot.ScoringShare,
+		})
+	}
+
+	return players
+}
+
+// clampRating keeps a player rating within the 1-100 range
+func clampRating(rating int) int {
+	if rating < 1 {
+		return 1
+	}
+	if rating > 100 {
+		return 100
+	}
+	return rating
+}
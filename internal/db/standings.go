@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"sort"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+)
+
+// CalculateStandings builds the league table from the played matches
+func CalculateStandings(ctx context.Context, store Store) ([]models.Standing, error) {
+	return calculateStandings(ctx, store, MatchFilter{Played: Played(true)})
+}
+
+// CalculateStandingsAfterWeek builds the league table from the played matches
+// of the given week and the weeks before it
+func CalculateStandingsAfterWeek(ctx context.Context, store Store, week uint) ([]models.Standing, error) {
+	return calculateStandings(ctx, store, MatchFilter{UpToWeek: week, Played: Played(true)})
+}
+
+// calculateStandings builds the league table from the matches selected by filter
+func calculateStandings(ctx context.Context, store Store, filter MatchFilter) ([]models.Standing, error) {
+	teams, err := store.Teams().ListTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := store.Matches().ListMatches(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group the played matches by home and away team
+	homeGames := make(map[uint][]models.Match)
+	awayGames := make(map[uint][]models.Match)
+	for _, match := range matches {
+		homeGames[match.HomeTeamID] = append(homeGames[match.HomeTeamID], match)
+		awayGames[match.AwayTeamID] = append(awayGames[match.AwayTeamID], match)
+	}
+	for i := range teams {
+		teams[i].HomeGames = homeGames[teams[i].ID]
+		teams[i].AwayGames = awayGames[teams[i].ID]
+	}
+
+	var standings []models.Standing
+	for _, team := range teams {
+		standing := models.Standing{
+			TeamID:   team.ID,
+			TeamName: team.Name,
+		}
+
+		// Calculate match statistics
+		for _, match := range team.HomeGames {
+			if match.HomeGoals != nil && match.AwayGoals != nil {
+				standing.Played++
+				standing.GoalsFor += uint(*match.HomeGoals) // Assuming HomeGoals is *int or similar, casting to uint
+				standing.GoalsAgainst += uint(*match.AwayGoals)
+				if *match.HomeGoals > *match.AwayGoals {
+					standing.Won++
+					standing.Points += 3
+				} else if *match.HomeGoals < *match.AwayGoals {
+					standing.Lost++
+				} else {
+					standing.Drawn++
+					standing.Points++
+				}
+			}
+		}
+
+		for _, match := range team.AwayGames {
+			if match.HomeGoals != nil && match.AwayGoals != nil {
+				standing.Played++
+				standing.GoalsFor += uint(*match.AwayGoals) // Team was away, so AwayGoals are their scored goals
+				standing.GoalsAgainst += uint(*match.HomeGoals)
+				if *match.AwayGoals > *match.HomeGoals {
+					standing.Won++
+					standing.Points += 3
+				} else if *match.AwayGoals < *match.HomeGoals {
+					standing.Lost++
+				} else {
+					standing.Drawn++
+					standing.Points++
+				}
+			}
+		}
+
+		standing.GoalDifference = int(standing.GoalsFor) - int(standing.GoalsAgainst)
+		standings = append(standings, standing)
+	}
+
+	// Sort standings (by points and goal difference)
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Points != standings[j].Points {
+			return standings[i].Points > standings[j].Points
+		}
+		return standings[i].GoalDifference > standings[j].GoalDifference
+	})
+
+	return standings, nil
+}
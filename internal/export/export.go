@@ -0,0 +1,285 @@
+// Package export converts the league in a store into documents that can be
+// taken into other tools
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/db"
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+)
+
+// Datasets of a league export
+const (
+	DatasetTeams       = "teams"
+	DatasetMatches     = "matches" // The whole fixture, unplayed matches included
+	DatasetResults     = "results" // Played matches with their outcome
+	DatasetStandings   = "standings"
+	DatasetPredictions = "predictions"
+)
+
+// Datasets lists the datasets in the order they are exported
+var Datasets = []string{DatasetTeams, DatasetMatches, DatasetResults, DatasetStandings, DatasetPredictions}
+
+// Formats of a league export
+const (
+	FormatJSON = "json" // The complete league as one document
+	FormatCSV  = "csv"  // One dataset as a table
+	FormatXLSX = "xlsx" // An Excel workbook with one worksheet per dataset
+)
+
+// Formats lists the supported export formats
+var Formats = []string{FormatJSON, FormatCSV, FormatXLSX}
+
+// Export errors
+var (
+	ErrUnknownFormat  = errors.New("unknown export format")
+	ErrUnknownDataset = errors.New("unknown dataset")
+)
+
+// Team is an exported team
+type Team struct {
+	ID      uint   `json:"id"`
+	Name    string `json:"name"`
+	Attack  int    `json:"attack"`  // Offensive strength of the team
+	Defense int    `json:"defense"` // Defensive strength of the team
+}
+
+// Match is an exported fixture with its result
+type Match struct {
+	ID           uint       `json:"id"`
+	Week         uint       `json:"week"`
+	HomeTeamID   uint       `json:"home_team_id"`
+	HomeTeamName string     `json:"home_team_name"`
+	AwayTeamID   uint       `json:"away_team_id"`
+	AwayTeamName string     `json:"away_team_name"`
+	HomeGoals    *uint      `json:"home_goals"`          // nil if not played
+	AwayGoals    *uint      `json:"away_goals"`          // nil if not played
+	PlayedAt     *time.Time `json:"played_at,omitempty"` // nil if not played
+}
+
+// Prediction is an exported championship prediction
+type Prediction struct {
+	Week        uint      `json:"week"`
+	TeamID      uint      `json:"team_id"`
+	TeamName    string    `json:"team_name"`
+	Probability float64   `json:"probability"` // Championship probability in percent
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// League is a complete export of the league
+type League struct {
+	ExportedAt  time.Time         `json:"exported_at"`
+	Teams       []Team            `json:"teams"`
+	Matches     []Match           `json:"matches"`
+	Standings   []models.Standing `json:"standings"`
+	Predictions []Prediction      `json:"predictions"` // Predictions of every week, oldest week first
+}
+
+// Build reads the league in store
+func Build(ctx context.Context, store db.Store) (*League, error) {
+	teams, err := store.Teams().ListTeams(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading teams: %w", err)
+	}
+	matches, err := store.Matches().ListMatches(ctx, db.MatchFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("error reading matches: %w", err)
+	}
+	standings, err := db.CalculateStandings(ctx, store)
+	if err != nil {
+		return nil, fmt.Errorf("error calculating standings: %w", err)
+	}
+
+	league := &League{
+		ExportedAt:  time.Now(),
+		Teams:       make([]Team, 0, len(teams)),
+		Matches:     make([]Match, 0, len(matches)),
+		Standings:   standings,
+		Predictions: []Prediction{},
+	}
+
+	teamNames := make(map[uint]string, len(teams))
+	for _, team := range teams {
+		teamNames[team.ID] = team.Name
+		league.Teams = append(league.Teams, Team{ID: team.ID, Name: team.Name, Attack: team.Attack, Defense: team.Defense})
+	}
+
+	var lastWeek uint
+	for _, match := range matches {
+		exported := Match{
+			ID:           match.ID,
+			Week:         match.Week,
+			HomeTeamID:   match.HomeTeamID,
+			HomeTeamName: teamNames[match.HomeTeamID],
+			AwayTeamID:   match.AwayTeamID,
+			AwayTeamName: teamNames[match.AwayTeamID],
+			HomeGoals:    match.HomeGoals,
+			AwayGoals:    match.AwayGoals,
+		}
+		if match.HomeGoals != nil && match.AwayGoals != nil {
+			playedAt := match.PlayedAt
+			exported.PlayedAt = &playedAt
+		}
+		league.Matches = append(league.Matches, exported)
+		lastWeek = max(lastWeek, match.Week)
+	}
+
+	// Week 0 holds the predictions made before any match was played
+	for week := uint(0); week <= lastWeek; week++ {
+		predictions, err := store.Predictions().ListPredictions(ctx, week)
+		if err != nil {
+			return nil, fmt.Errorf("error reading predictions: %w", err)
+		}
+		for _, prediction := range predictions {
+			league.Predictions = append(league.Predictions, Prediction{
+				Week:        prediction.Week,
+				TeamID:      prediction.TeamID,
+				TeamName:    teamNames[prediction.TeamID],
+				Probability: prediction.Probability,
+				CreatedAt:   prediction.CreatedAt,
+			})
+		}
+	}
+
+	return league, nil
+}
+
+// Write writes the league in format; dataset selects the table of a CSV export
+func Write(w io.Writer, league *League, format, dataset string) error {
+	switch format {
+	case FormatJSON:
+		return WriteJSON(w, league)
+	case FormatCSV:
+		return WriteCSV(w, league, dataset)
+	case FormatXLSX:
+		return WriteXLSX(w, league)
+	default:
+		return fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+}
+
+// ContentType returns the MIME type of an export format
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "application/json; charset=utf-8"
+	}
+}
+
+// WriteJSON writes the complete league as indented JSON
+func WriteJSON(w io.Writer, league *League) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(league)
+}
+
+// WriteCSV writes one dataset of the league as CSV with a header row
+func WriteCSV(w io.Writer, league *League, dataset string) error {
+	rows, err := Rows(league, dataset)
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// Rows returns one dataset of the league as a header row followed by one row
+// per record
+func Rows(league *League, dataset string) ([][]string, error) {
+	var rows [][]string
+	switch dataset {
+	case DatasetTeams:
+		rows = append(rows, []string{"id", "name", "attack", "defense"})
+		for _, team := range league.Teams {
+			rows = append(rows, []string{formatUint(team.ID), team.Name, strconv.Itoa(team.Attack), strconv.Itoa(team.Defense)})
+		}
+	case DatasetMatches:
+		rows = append(rows, []string{"id", "week", "home_team_id", "home_team_name", "away_team_id", "away_team_name", "home_goals", "away_goals", "played_at"})
+		for _, match := range league.Matches {
+			playedAt := ""
+			if match.PlayedAt != nil {
+				playedAt = match.PlayedAt.Format(time.RFC3339)
+			}
+			rows = append(rows, []string{
+				formatUint(match.ID), formatUint(match.Week),
+				formatUint(match.HomeTeamID), match.HomeTeamName,
+				formatUint(match.AwayTeamID), match.AwayTeamName,
+				formatGoals(match.HomeGoals), formatGoals(match.AwayGoals), playedAt,
+			})
+		}
+	case DatasetResults:
+		rows = append(rows, []string{"match_id", "week", "played_at", "home_team_name", "away_team_name", "home_goals", "away_goals", "result"})
+		for _, match := range league.Matches {
+			if match.PlayedAt == nil {
+				continue
+			}
+			rows = append(rows, []string{
+				formatUint(match.ID), formatUint(match.Week), match.PlayedAt.Format(time.RFC3339),
+				match.HomeTeamName, match.AwayTeamName,
+				formatGoals(match.HomeGoals), formatGoals(match.AwayGoals), matchResult(*match.HomeGoals, *match.AwayGoals),
+			})
+		}
+	case DatasetStandings:
+		rows = append(rows, []string{"position", "team_id", "team_name", "played", "won", "drawn", "lost", "goals_for", "goals_against", "goal_difference", "points"})
+		for i, standing := range league.Standings {
+			rows = append(rows, []string{
+				strconv.Itoa(i + 1), formatUint(standing.TeamID), standing.TeamName,
+				formatUint(standing.Played), formatUint(standing.Won), formatUint(standing.Drawn), formatUint(standing.Lost),
+				formatUint(standing.GoalsFor), formatUint(standing.GoalsAgainst), strconv.Itoa(standing.GoalDifference),
+				formatUint(standing.Points),
+			})
+		}
+	case DatasetPredictions:
+		rows = append(rows, []string{"week", "team_id", "team_name", "probability", "created_at"})
+		for _, prediction := range league.Predictions {
+			rows = append(rows, []string{
+				formatUint(prediction.Week), formatUint(prediction.TeamID), prediction.TeamName,
+				strconv.FormatFloat(prediction.Probability, 'f', 2, 64), prediction.CreatedAt.Format(time.RFC3339),
+			})
+		}
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownDataset, dataset)
+	}
+	return rows, nil
+}
+
+// formatUint formats an unsigned number for a CSV cell
+func formatUint(value uint) string {
+	return strconv.FormatUint(uint64(value), 10)
+}
+
+// matchResult returns H for a home win, D for a draw and A for an away win,
+// like the FTR column of football-data files
+func matchResult(homeGoals, awayGoals uint) string {
+	switch {
+	case homeGoals > awayGoals:
+		return "H"
+	case homeGoals < awayGoals:
+		return "A"
+	default:
+		return "D"
+	}
+}
+
+// formatGoals formats a score, leaving the cell empty if the match is not played
+func formatGoals(goals *uint) string {
+	if goals == nil {
+		return ""
+	}
+	return formatUint(*goals)
+}
@@ -0,0 +1,138 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// XLSX package parts that do not depend on the league
+const (
+	xlsxContentTypesHead = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`
+
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	// Style 1 is the bold font of the header rows
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+		`</styleSheet>`
+)
+
+// WriteXLSX writes the league as an Excel workbook with one worksheet per dataset
+// Numbers are stored as numeric cells and the header row of each sheet is bold
+// and frozen.
+func WriteXLSX(w io.Writer, league *League) error {
+	archive := zip.NewWriter(w)
+
+	contentTypes := xlsxContentTypesHead
+	workbook := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`
+	workbookRels := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`
+
+	for i, dataset := range Datasets {
+		rows, err := Rows(league, dataset)
+		if err != nil {
+			return err
+		}
+		sheet := i + 1
+		if err := writeZipFile(archive, fmt.Sprintf("xl/worksheets/sheet%d.xml", sheet), worksheet(rows)); err != nil {
+			return err
+		}
+		contentTypes += fmt.Sprintf(`<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, sheet)
+		workbook += fmt.Sprintf(`<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeXML(dataset), sheet, sheet)
+		workbookRels += fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, sheet, sheet)
+	}
+	workbookRels += fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(Datasets)+1)
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", contentTypes + `</Types>`},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", workbook + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", workbookRels + `</Relationships>`},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, part := range parts {
+		if err := writeZipFile(archive, part.name, part.content); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// worksheet renders rows as the XML of a worksheet whose first row is the header
+func worksheet(rows [][]string) string {
+	var sheet strings.Builder
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	sheet.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews><sheetData>`)
+	for r, row := range rows {
+		fmt.Fprintf(&sheet, `<row r="%d">`, r+1)
+		for c, value := range row {
+			ref := columnName(c) + strconv.Itoa(r+1)
+			switch {
+			case r == 0:
+				fmt.Fprintf(&sheet, `<c r="%s" t="inlineStr" s="1"><is><t>%s</t></is></c>`, ref, escapeXML(value))
+			case value == "":
+				// Empty cells, such as the score of an unplayed match, are left out
+			case isNumber(value):
+				fmt.Fprintf(&sheet, `<c r="%s"><v>%s</v></c>`, ref, value)
+			default:
+				fmt.Fprintf(&sheet, `<c r="%s" t="inlineStr"><is><t>%s</t></is></c>`, ref, escapeXML(value))
+			}
+		}
+		sheet.WriteString(`</row>`)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+	return sheet.String()
+}
+
+// writeZipFile adds a file to the archive
+func writeZipFile(archive *zip.Writer, name, content string) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(file, content)
+	return err
+}
+
+// columnName returns the spreadsheet column name of a zero-based index (A, B, ..., AA)
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// isNumber reports whether a cell value is stored as a number
+func isNumber(value string) bool {
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil && !strings.ContainsAny(value, "xXeEnN")
+}
+
+// escapeXML escapes a value for use in XML text and attributes
+func escapeXML(value string) string {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(value))
+	return escaped.String()
+}
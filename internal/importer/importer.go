@@ -0,0 +1,336 @@
+// Package importer reads historical fixtures and results from CSV files in the
+// layout published by football-data.co.uk and loads them as a league
+package importer
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/db"
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+	simModels "github.com/tarikbacak/insider-league-simulator/internal/simulator/models"
+)
+
+// Import errors
+var (
+	ErrMissingColumns = errors.New("the CSV file does not have the required columns")
+	ErrNoMatches      = errors.New("the CSV file has no match that could be imported")
+)
+
+// columnAliases lists the header names of each column, in the main layout
+// (HomeTeam, FTHG, ...) and in the layout of the extra leagues (Home, HG, ...)
+var columnAliases = map[string][]string{
+	"division":   {"Div", "League"},
+	"season":     {"Season"},
+	"date":       {"Date"},
+	"time":       {"Time"},
+	"home_team":  {"HomeTeam", "Home"},
+	"away_team":  {"AwayTeam", "Away"},
+	"home_goals": {"FTHG", "HG"},
+	"away_goals": {"FTAG", "AG"},
+}
+
+// requiredColumns must be present in the header row
+var requiredColumns = []string{"date", "home_team", "away_team", "home_goals", "away_goals"}
+
+// dateLayouts are the date formats used by football-data files
+var dateLayouts = []string{"02/01/2006", "02/01/06", "2006-01-02"}
+
+// Match is a fixture read from a CSV row
+type Match struct {
+	Line      int       // Line of the row in the file
+	Week      uint      // Assigned after reading, see assignWeeks
+	PlayedAt  time.Time // Date and, if given, kick-off time of the match
+	HomeTeam  string
+	AwayTeam  string
+	HomeGoals *uint // nil if the match has not been played
+	AwayGoals *uint // nil if the match has not been played
+}
+
+// SkippedRow is a CSV row that could not be mapped to a match
+type SkippedRow struct {
+	Line   int    `json:"line" example:"14"`
+	Reason string `json:"reason" example:"invalid home goals \"x\""`
+}
+
+// Season is the content of a CSV file
+type Season struct {
+	Division string       // Division of the imported rows, if the file has one
+	Name     string       // Season of the imported rows, if the file has one
+	Teams    []string     // Team names in alphabetical order
+	Matches  []Match      // Matches ordered by week and date
+	Skipped  []SkippedRow // Rows that could not be mapped, in file order
+}
+
+// Result summarizes a loaded season
+type Result struct {
+	Teams       int          `json:"teams" example:"20"`
+	Matches     int          `json:"matches" example:"380"`
+	Played      int          `json:"played" example:"380"`
+	Weeks       uint         `json:"weeks" example:"38"`
+	SkippedRows []SkippedRow `json:"skipped_rows"`
+}
+
+// Parse reads a football-data CSV file. Rows that cannot be mapped to a match
+// are reported in Season.Skipped instead of failing the import; rows of another
+// division or season than the first imported row are skipped too. Matches
+// without a score are imported as unplayed fixtures. Weeks are assigned in date
+// order: each match is played in the week after the last match of both teams.
+func Parse(r io.Reader) (*Season, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: the file is empty", ErrMissingColumns)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading CSV header: %w", err)
+	}
+	columns := mapColumns(header)
+	var missing []string
+	for _, column := range requiredColumns {
+		if _, ok := columns[column]; !ok {
+			missing = append(missing, columnAliases[column][0])
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrMissingColumns, strings.Join(missing, ", "))
+	}
+
+	season := &Season{Skipped: []SkippedRow{}}
+	fixtures := make(map[[2]string]int) // Home and away team -> line of the first row
+	teams := make(map[string]bool)
+	first := true
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			season.Skipped = append(season.Skipped, SkippedRow{Line: parseErr.StartLine, Reason: parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		field := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue // Files often end with rows of empty cells
+		}
+
+		match, reason := parseRow(field)
+		if reason == "" && !first && field("division") != season.Division {
+			reason = fmt.Sprintf("division %q differs from %q", field("division"), season.Division)
+		}
+		if reason == "" && !first && field("season") != season.Name {
+			reason = fmt.Sprintf("season %q differs from %q", field("season"), season.Name)
+		}
+		if reason == "" {
+			key := [2]string{match.HomeTeam, match.AwayTeam}
+			if previous, ok := fixtures[key]; ok {
+				reason = fmt.Sprintf("%s vs %s was already imported from line %d", match.HomeTeam, match.AwayTeam, previous)
+			} else {
+				fixtures[key] = line
+			}
+		}
+		if reason != "" {
+			season.Skipped = append(season.Skipped, SkippedRow{Line: line, Reason: reason})
+			continue
+		}
+
+		if first {
+			season.Division, season.Name = field("division"), field("season")
+			first = false
+		}
+		match.Line = line
+		teams[match.HomeTeam], teams[match.AwayTeam] = true, true
+		season.Matches = append(season.Matches, match)
+	}
+
+	if len(season.Matches) == 0 {
+		return nil, ErrNoMatches
+	}
+	for team := range teams {
+		season.Teams = append(season.Teams, team)
+	}
+	sort.Strings(season.Teams)
+	assignWeeks(season.Matches)
+
+	return season, nil
+}
+
+// mapColumns maps the column names of columnAliases to their index in header
+func mapColumns(header []string) map[string]int {
+	columns := make(map[string]int)
+	for i, name := range header {
+		// Excel saves a byte order mark before the first header
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		for column, aliases := range columnAliases {
+			for _, alias := range aliases {
+				if _, seen := columns[column]; !seen && strings.EqualFold(name, alias) {
+					columns[column] = i
+				}
+			}
+		}
+	}
+	return columns
+}
+
+// parseRow maps the fields of a row to a match, or returns why it cannot
+func parseRow(field func(column string) string) (Match, string) {
+	match := Match{HomeTeam: field("home_team"), AwayTeam: field("away_team")}
+	switch {
+	case match.HomeTeam == "" || match.AwayTeam == "":
+		return match, "home or away team is missing"
+	case match.HomeTeam == match.AwayTeam:
+		return match, fmt.Sprintf("%s cannot play against itself", match.HomeTeam)
+	}
+
+	playedAt, err := parseDate(field("date"), field("time"))
+	if err != nil {
+		return match, err.Error()
+	}
+	match.PlayedAt = playedAt
+
+	homeGoals, awayGoals := field("home_goals"), field("away_goals")
+	if homeGoals == "" && awayGoals == "" {
+		return match, "" // Not played yet
+	}
+	if match.HomeGoals, err = parseGoals(homeGoals); err != nil {
+		return match, fmt.Sprintf("invalid home goals %q", homeGoals)
+	}
+	if match.AwayGoals, err = parseGoals(awayGoals); err != nil {
+		return match, fmt.Sprintf("invalid away goals %q", awayGoals)
+	}
+	return match, ""
+}
+
+// parseDate parses the date of a match and its kick-off time if given
+func parseDate(date, kickOff string) (time.Time, error) {
+	if date == "" {
+		return time.Time{}, errors.New("date is missing")
+	}
+	for _, layout := range dateLayouts {
+		day, err := time.Parse(layout, date)
+		if err != nil {
+			continue
+		}
+		if kickOff == "" {
+			return day, nil
+		}
+		clock, err := time.Parse("15:04", kickOff)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time %q", kickOff)
+		}
+		return day.Add(time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", date)
+}
+
+// parseGoals parses a non-negative score
+func parseGoals(value string) (*uint, error) {
+	goals, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	result := uint(goals)
+	return &result, nil
+}
+
+// assignWeeks numbers the weeks of the matches in date order and sorts them by week
+// A match is played in the week after the last match of either team, so every
+// team plays at most once a week and a postponed match moves to a later week.
+func assignWeeks(matches []Match) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].PlayedAt.Before(matches[j].PlayedAt)
+	})
+	lastWeek := make(map[string]uint)
+	for i := range matches {
+		match := &matches[i]
+		match.Week = max(lastWeek[match.HomeTeam], lastWeek[match.AwayTeam]) + 1
+		lastWeek[match.HomeTeam], lastWeek[match.AwayTeam] = match.Week, match.Week
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Week < matches[j].Week
+	})
+}
+
+// Load creates the teams and matches of season in an empty store
+// Team strengths for the Poisson model are fitted from the played matches: the
+// goals a team scored and conceded per match relative to the league average.
+// Teams without a played match get average strength.
+func Load(ctx context.Context, store db.Store, season *Season) (*Result, error) {
+	tallies := make(map[string]*simModels.GoalTally, len(season.Teams))
+	for _, team := range season.Teams {
+		tallies[team] = &simModels.GoalTally{}
+	}
+	result := &Result{Teams: len(season.Teams), Matches: len(season.Matches), SkippedRows: season.Skipped}
+	var goals uint
+	for _, match := range season.Matches {
+		result.Weeks = max(result.Weeks, match.Week)
+		if match.HomeGoals == nil || match.AwayGoals == nil {
+			continue
+		}
+		result.Played++
+		goals += *match.HomeGoals + *match.AwayGoals
+		tallies[match.HomeTeam].Add(*match.HomeGoals, *match.AwayGoals)
+		tallies[match.AwayTeam].Add(*match.AwayGoals, *match.HomeGoals)
+	}
+
+	// Goals per team per match; the model's default if nothing has been played
+	average := simModels.AverageGoals(goals, uint(result.Played))
+
+	teams := make([]models.Team, 0, len(season.Teams))
+	stats := make([]models.TeamStats, 0, len(season.Teams))
+	for _, name := range season.Teams {
+		fitted := simModels.FitStats(*tallies[name], average)
+		teamStats := models.TeamStats{
+			AvgScored:       fitted.AvgScored,
+			AvgConceded:     fitted.AvgConceded,
+			AttackStrength:  fitted.AttackStrength,
+			DefenseStrength: fitted.DefenseStrength,
+		}
+		stats = append(stats, teamStats)
+		// Ratings use the same scale as the generated league (strength 1 = 75)
+		teams = append(teams, models.Team{Name: name, Attack: rating(teamStats.AttackStrength), Defense: rating(teamStats.DefenseStrength)})
+	}
+
+	matches := make([]models.Match, 0, len(season.Matches))
+	for _, match := range season.Matches {
+		matches = append(matches, models.Match{
+			Week:      match.Week,
+			HomeTeam:  models.Team{Name: match.HomeTeam},
+			AwayTeam:  models.Team{Name: match.AwayTeam},
+			HomeGoals: match.HomeGoals,
+			AwayGoals: match.AwayGoals,
+			PlayedAt:  match.PlayedAt,
+		})
+	}
+
+	if err := db.ImportLeague(ctx, store, teams, stats, matches); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// rating converts a team strength to a 1-100 team rating
+func rating(strength float64) int {
+	return int(math.Max(1, math.Min(100, math.Round(strength*75))))
+}
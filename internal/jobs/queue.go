@@ -0,0 +1,331 @@
+// Package jobs runs long operations in the background
+// Jobs are stored in the database so their progress and results can be read
+// by ID, and are executed by a fixed pool of workers. Each running job has its
+// own context, which is cancelled when the job is cancelled or the queue stops.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/db"
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/base"
+	"gorm.io/gorm"
+)
+
+// Queue settings
+const (
+	// DefaultWorkers is how many jobs run at the same time
+	DefaultWorkers = 2
+	// maxQueuedJobs is how many jobs can wait for a worker
+	maxQueuedJobs = 100
+	// progressStep is the smallest progress change (in percent) written to the database
+	progressStep = 1.0
+)
+
+// Job queue errors
+var (
+	ErrUnknownJobType = errors.New("unknown job type")
+	ErrQueueFull      = errors.New("job queue is full")
+	ErrJobFinished    = errors.New("job has already finished")
+)
+
+// Handler executes a job
+// params holds the JSON parameters the job was submitted with. The handler
+// must stop when ctx is cancelled and may report its progress. The returned
+// value is stored as the job's JSON result. A handler that stops after doing
+// part of its work may return a result together with the error; it is stored
+// with the failed or cancelled job.
+type Handler func(ctx context.Context, params json.RawMessage, progress base.ProgressFunc) (interface{}, error)
+
+// Queue dispatches submitted jobs to a pool of workers
+type Queue struct {
+	db      *gorm.DB
+	workers int
+	pending chan uint
+
+	mu       sync.Mutex
+	handlers map[string]Handler
+	running  map[uint]context.CancelFunc
+}
+
+// NewQueue creates a queue that stores its jobs in database and runs them on
+// the given number of workers
+func NewQueue(database *gorm.DB, workers int) *Queue {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Queue{
+		db:       database,
+		workers:  workers,
+		pending:  make(chan uint, maxQueuedJobs),
+		handlers: make(map[string]Handler),
+		running:  make(map[uint]context.CancelFunc),
+	}
+}
+
+// Register sets the handler of a job type
+func (q *Queue) Register(jobType string, handler Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[jobType] = handler
+}
+
+// Types returns the registered job types, sorted by name
+func (q *Queue) Types() []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	types := make([]string, 0, len(q.handlers))
+	for jobType := range q.handlers {
+		types = append(types, jobType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Start runs the workers until ctx is cancelled
+// Jobs left running by a previous process are marked failed, and jobs that
+// were still queued are queued again.
+func (q *Queue) Start(ctx context.Context) error {
+	database := q.db
+	now := time.Now()
+	if err := database.Model(&models.Job{}).Where("status = ?", models.JobRunning).Updates(map[string]interface{}{
+		"status":      models.JobFailed,
+		"error":       "job was interrupted by a server restart",
+		"finished_at": &now,
+	}).Error; err != nil {
+		return err
+	}
+
+	var queued []models.Job
+	if err := database.Where("status = ?", models.JobQueued).Order("id").Find(&queued).Error; err != nil {
+		return err
+	}
+	for _, job := range queued {
+		select {
+		case q.pending <- job.ID:
+		default:
+			q.finish(database, job.ID, models.JobFailed, "", ErrQueueFull)
+		}
+	}
+
+	for i := 0; i < q.workers; i++ {
+		go q.work(ctx)
+	}
+	return nil
+}
+
+// Submit stores a job and queues it for a worker
+func (q *Queue) Submit(jobType string, params json.RawMessage) (models.Job, error) {
+	q.mu.Lock()
+	_, ok := q.handlers[jobType]
+	q.mu.Unlock()
+	if !ok {
+		return models.Job{}, fmt.Errorf("%w %q (valid types: %s)", ErrUnknownJobType, jobType, strings.Join(q.Types(), ", "))
+	}
+	if len(params) == 0 {
+		params = json.RawMessage("{}")
+	}
+
+	job := models.Job{
+		Type:   jobType,
+		Params: string(params),
+		Status: models.JobQueued,
+	}
+	database := q.db
+	if err := database.Create(&job).Error; err != nil {
+		return job, err
+	}
+
+	select {
+	case q.pending <- job.ID:
+		return job, nil
+	default:
+		q.finish(database, job.ID, models.JobFailed, "", ErrQueueFull)
+		return job, ErrQueueFull
+	}
+}
+
+// Cancel stops a job
+// A queued job is cancelled immediately; a running job's context is cancelled
+// and the job is marked cancelled once its handler returns.
+func (q *Queue) Cancel(id uint) (models.Job, error) {
+	database := q.db
+
+	result := database.Model(&models.Job{}).
+		Where("id = ? AND status = ?", id, models.JobQueued).
+		Updates(map[string]interface{}{
+			"status":      models.JobCancelled,
+			"error":       context.Canceled.Error(),
+			"finished_at": time.Now(),
+		})
+	if result.Error != nil {
+		return models.Job{}, result.Error
+	}
+
+	q.mu.Lock()
+	cancel, running := q.running[id]
+	q.mu.Unlock()
+	if running {
+		cancel()
+	}
+
+	job, err := db.FindJob(database, id)
+	if err != nil {
+		return job, err
+	}
+	if result.RowsAffected == 0 && !running && job.Status != models.JobQueued && job.Status != models.JobRunning {
+		return job, fmt.Errorf("%w (status: %s)", ErrJobFinished, job.Status)
+	}
+	return job, nil
+}
+
+// List returns the newest jobs first, optionally only those with the given status
+func (q *Queue) List(ctx context.Context, status string, limit int) ([]models.Job, error) {
+	query := q.db.WithContext(ctx).Model(&models.Job{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var list []models.Job
+	err := query.Order("id DESC").Limit(limit).Find(&list).Error
+	return list, err
+}
+
+// Get loads a job by ID, returning db.ErrJobNotFound if it does not exist
+func (q *Queue) Get(ctx context.Context, id uint) (models.Job, error) {
+	return db.FindJob(q.db.WithContext(ctx), id)
+}
+
+// work runs queued jobs until ctx is cancelled
+func (q *Queue) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-q.pending:
+			q.run(ctx, id)
+		}
+	}
+}
+
+// run executes a single job and stores its outcome
+func (q *Queue) run(ctx context.Context, id uint) {
+	database := q.db
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Register the job as running before claiming it so that a Cancel call
+	// between the two always reaches the job
+	q.mu.Lock()
+	q.running[id] = cancel
+	q.mu.Unlock()
+	defer func() {
+		q.mu.Lock()
+		delete(q.running, id)
+		q.mu.Unlock()
+	}()
+
+	now := time.Now()
+	claim := database.Model(&models.Job{}).
+		Where("id = ? AND status = ?", id, models.JobQueued).
+		Updates(map[string]interface{}{"status": models.JobRunning, "started_at": &now})
+	if claim.Error != nil {
+		log.Printf("Could not start job %d: %v", id, claim.Error)
+		return
+	}
+	if claim.RowsAffected == 0 {
+		return // Cancelled while queued
+	}
+
+	job, err := db.FindJob(database, id)
+	if err != nil {
+		log.Printf("Could not load job %d: %v", id, err)
+		return
+	}
+
+	q.mu.Lock()
+	handler := q.handlers[job.Type]
+	q.mu.Unlock()
+
+	result, err := q.execute(jobCtx, database, job, handler)
+	switch {
+	case err == nil:
+		q.finish(database, id, models.JobSucceeded, result, nil)
+	case jobCtx.Err() != nil && errors.Is(err, context.Canceled):
+		q.finish(database, id, models.JobCancelled, result, err)
+	default:
+		q.finish(database, id, models.JobFailed, result, err)
+	}
+}
+
+// execute calls the job's handler, reporting progress and recovering from panics
+func (q *Queue) execute(ctx context.Context, database *gorm.DB, job models.Job, handler Handler) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	lastProgress := 0.0
+	progress := func(done, total int) {
+		if total <= 0 {
+			return
+		}
+		percent := float64(done) * 100 / float64(total)
+		if percent > 100 {
+			percent = 100
+		}
+		if percent-lastProgress < progressStep && percent < 100 {
+			return
+		}
+		lastProgress = percent
+		if err := database.Model(&models.Job{}).Where("id = ?", job.ID).
+			Update("progress", percent).Error; err != nil {
+			log.Printf("Could not update progress of job %d: %v", job.ID, err)
+		}
+	}
+
+	output, err := handler(ctx, json.RawMessage(job.Params), progress)
+	if output == nil {
+		return "", err
+	}
+	encoded, encodeErr := json.Marshal(output)
+	if encodeErr != nil {
+		if err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("could not encode job result: %v", encodeErr)
+	}
+	return string(encoded), err
+}
+
+// finish stores the final status of a job
+func (q *Queue) finish(database *gorm.DB, id uint, status, result string, jobErr error) {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":      status,
+		"finished_at": &now,
+	}
+	if status == models.JobSucceeded {
+		updates["progress"] = 100.0
+	}
+	if result != "" {
+		updates["result"] = result
+	}
+	if jobErr != nil {
+		updates["error"] = jobErr.Error()
+	}
+	if err := database.Model(&models.Job{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		log.Printf("Could not save outcome of job %d: %v", id, err)
+	}
+}
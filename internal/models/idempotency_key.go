@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// IdempotencyKey stores the outcome of a request sent with an Idempotency-Key header
+// so that retries of the same request are answered without repeating its side effects.
+type IdempotencyKey struct {
+	Key        string    `json:"key" gorm:"primaryKey;size:255"` // Client supplied key
+	Method     string    `json:"method" gorm:"size:10"`          // HTTP method of the original request
+	Path       string    `json:"path" gorm:"size:512"`           // Request path including the query string
+	StatusCode int       `json:"status_code"`                    // Response status; 0 while the request is still in progress
+	Response   string    `json:"response" gorm:"type:text"`      // Response body returned to the original request
+	CreatedAt  time.Time `json:"created_at"`                     // When the key was first used
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Background job statuses
+const (
+	JobQueued    = "queued"    // Waiting for a free worker
+	JobRunning   = "running"   // Being executed by a worker
+	JobSucceeded = "succeeded" // Finished; Result holds its output
+	JobFailed    = "failed"    // Finished with an error
+	JobCancelled = "cancelled" // Cancelled before it finished
+)
+
+// Job is a long-running operation executed in the background by the job queue.
+type Job struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`             // Unique ID of the job
+	Type       string     `json:"type" gorm:"size:64"`              // Operation to run, e.g. predictions
+	Params     string     `json:"-" gorm:"type:text"`               // JSON encoded parameters of the operation
+	Status     string     `json:"status" gorm:"size:16;index"`      // queued, running, succeeded, failed or cancelled
+	Progress   float64    `json:"progress"`                         // Completion percentage (0-100)
+	Result     string     `json:"-" gorm:"type:text"`               // JSON encoded output of a succeeded job
+	Error      string     `json:"error,omitempty" gorm:"type:text"` // Error of a failed or cancelled job
+	CreatedAt  time.Time  `json:"created_at" gorm:"index"`          // When the job was submitted
+	StartedAt  *time.Time `json:"started_at,omitempty"`             // When a worker started the job
+	FinishedAt *time.Time `json:"finished_at,omitempty"`            // When the job finished
+}
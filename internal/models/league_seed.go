@@ -0,0 +1,11 @@
+package models
+
+// LeagueSeed is the random seed that the matches of the league are simulated with
+// Every simulated match, including a re-simulation, uses a seed derived from
+// Seed and the number of matches simulated before it, so a league with the
+// same seed and count plays the same results in any instance.
+type LeagueSeed struct {
+	ID      uint   `json:"-" gorm:"primaryKey;autoIncrement:false"` // Always 1; the league has a single seed
+	Seed    int64  `json:"seed"`                                    // Seed chosen when the league was created
+	Matches uint64 `json:"matches"`                                 // Matches simulated with the seed so far
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// LeagueSnapshot is a saved copy of the league's progress (results, match events,
+// injuries/suspensions and predictions) that the league can be restored to.
+type LeagueSnapshot struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`    // Unique ID of the snapshot
+	Label     string    `json:"label" gorm:"size:255"`   // Description of why the snapshot was taken
+	Week      uint      `json:"week"`                    // Last completed week when the snapshot was taken
+	Data      string    `json:"-" gorm:"type:text"`      // JSON encoded league state
+	CreatedAt time.Time `json:"created_at" gorm:"index"` // When the snapshot was taken
+}
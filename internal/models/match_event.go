@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Match event types
+const (
+	EventGoal         = "goal"
+	EventYellowCard   = "yellow_card"
+	EventRedCard      = "red_card"
+	EventSubstitution = "substitution"
+	EventInjury       = "injury"
+	EventHalfTime     = "half_time"
+	EventFullTime     = "full_time"
+)
+
+// MatchEvent represents a single entry in a match timeline.
+type MatchEvent struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`           // Unique ID of the event
+	MatchID        uint      `json:"match_id" gorm:"index;not null"` // ID of the match the event belongs to
+	Minute         uint      `json:"minute" gorm:"not null"`         // Minute of the match in which the event occurred (1-90)
+	Type           string    `json:"type" gorm:"size:32;not null"`   // Event type (goal, yellow_card, red_card, substitution, injury, half_time, full_time)
+	TeamID         *uint     `json:"team_id,omitempty"`              // Team the event belongs to (nil for half_time and full_time)
+	PlayerID       *uint     `json:"player_id,omitempty"`            // Scorer of a goal, player shown a card or injured player
+	AssistPlayerID *uint     `json:"assist_player_id,omitempty"`     // Player who assisted a goal
+	HomeScore      uint      `json:"home_score"`                     // Home team's score after the event
+	AwayScore      uint      `json:"away_score"`                     // Away team's score after the event
+	CreatedAt      time.Time `json:"created_at"`                     // Date and time the event was recorded
+}
@@ -0,0 +1,23 @@
+package models
+
+import "gorm.io/gorm"
+
+// Player positions
+const (
+	PositionGoalkeeper = "GK"
+	PositionDefender   = "DF"
+	PositionMidfielder = "MF"
+	PositionForward    = "FW"
+)
+
+// Player represents a member of a team's squad.
+type Player struct {
+	gorm.Model
+	TeamID       uint    `json:"team_id" gorm:"index;not null"`   // ID of the team the player belongs to
+	Team         Team    `json:"-" gorm:"foreignKey:TeamID"`      // Associated team (not exposed in JSON)
+	Name         string  `json:"name" gorm:"not null"`            // Name of the player
+	ShirtNumber  uint    `json:"shirt_number"`                    // Shirt number of the player
+	Position     string  `json:"position" gorm:"size:2;not null"` // Position of the player (GK, DF, MF, FW)
+	Rating       int     `json:"rating"`                          // Overall rating of the player (1-100)
+	ScoringShare float64 `json:"scoring_share"`                   // Relative share of the team's goals scored by the player
+}
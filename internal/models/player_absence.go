@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Absence reasons
+const (
+	AbsenceInjury     = "injury"
+	AbsenceSuspension = "suspension"
+)
+
+// PlayerAbsence represents a player being unavailable for a number of team matches
+// because of an injury or a suspension picked up in a match.
+type PlayerAbsence struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`            // Unique ID of the absence
+	PlayerID  uint      `json:"player_id" gorm:"index;not null"` // ID of the unavailable player
+	TeamID    uint      `json:"team_id" gorm:"index;not null"`   // ID of the player's team
+	MatchID   uint      `json:"match_id" gorm:"index;not null"`  // Match in which the injury or suspension was incurred
+	Week      uint      `json:"week" gorm:"not null"`            // Week of that match
+	Reason    string    `json:"reason" gorm:"size:16;not null"`  // Reason of the absence (injury, suspension)
+	Matches   uint      `json:"matches" gorm:"not null"`         // Number of team matches the player misses
+	CreatedAt time.Time `json:"created_at"`                      // Date and time the absence was recorded
+}
+
+// RemainingMatches returns how many more team matches the player misses,
+// given the weeks in which the team has already played
+func (pa *PlayerAbsence) RemainingMatches(playedWeeks []uint) uint {
+	var missed uint
+	for _, week := range playedWeeks {
+		if week > pa.Week {
+			missed++
+		}
+	}
+	if missed >= pa.Matches {
+		return 0
+	}
+	return pa.Matches - missed
+}
@@ -11,4 +11,5 @@ type Team struct {
 	HomeGames  []Match   `json:"home_games,omitempty" gorm:"foreignKey:HomeTeamID"` // Matches where the team is the home side
 	AwayGames  []Match   `json:"away_games,omitempty" gorm:"foreignKey:AwayTeamID"` // Matches where the team is the away side
 	Stats      TeamStats `json:"stats" gorm:"foreignKey:TeamID"`                    // Team statistics
+	Players    []Player  `json:"players,omitempty" gorm:"foreignKey:TeamID"`        // Squad of the team
 }
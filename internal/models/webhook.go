@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Webhook delivery statuses
+const (
+	DeliveryPending   = "pending"   // Waiting for its first or next attempt
+	DeliveryDelivered = "delivered" // Accepted by the receiver with a 2xx response
+	DeliveryFailed    = "failed"    // Given up after the maximum number of attempts
+)
+
+// Webhook is an HTTP endpoint that is notified of league events.
+type Webhook struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`          // Unique ID of the webhook
+	URL       string    `json:"url" gorm:"size:2048;not null"` // Endpoint the events are POSTed to
+	Secret    string    `json:"-" gorm:"size:255"`             // Key used to sign deliveries (never returned after creation)
+	Events    string    `json:"-" gorm:"size:512"`             // Comma separated subscribed events; empty means all events
+	Active    bool      `json:"active" gorm:"default:true"`    // Inactive webhooks receive no new deliveries
+	CreatedAt time.Time `json:"created_at"`                    // When the webhook was registered
+}
+
+// WebhookDelivery is a queued or completed delivery of one event to one webhook.
+type WebhookDelivery struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`                  // Unique ID of the delivery
+	WebhookID      uint       `json:"webhook_id" gorm:"index"`               // ID of the receiving webhook
+	Event          string     `json:"event" gorm:"size:64"`                  // Event type, e.g. week.completed
+	Payload        string     `json:"payload" gorm:"type:text"`              // JSON request body sent to the webhook
+	Status         string     `json:"status" gorm:"size:16;index"`           // pending, delivered or failed
+	Attempts       int        `json:"attempts"`                              // Number of delivery attempts made
+	NextAttemptAt  time.Time  `json:"next_attempt_at" gorm:"index"`          // When the next attempt is due
+	LastError      string     `json:"last_error,omitempty" gorm:"type:text"` // Error of the last failed attempt
+	ResponseStatus int        `json:"response_status,omitempty"`             // HTTP status of the last attempt
+	CreatedAt      time.Time  `json:"created_at"`                            // When the event was queued
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`                // When the receiver accepted the delivery
+}
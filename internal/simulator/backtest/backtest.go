@@ -0,0 +1,396 @@
+// Package backtest oynanmış sezonları hafta hafta yeniden oynatarak modellerin
+// maç sonucu ve şampiyonluk tahminlerini gerçekleşen sonuçlarla karşılaştırır
+package backtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/db"
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/base"
+	simModels "github.com/tarikbacak/insider-league-simulator/internal/simulator/models"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/montecarlo"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/poisson"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/squad"
+)
+
+// Değerlendirilen modeller
+const (
+	ModelPoisson    = "poisson"    // Poisson maç modelinden örneklenen maç sonucu olasılıkları
+	ModelBaseRate   = "base_rate"  // O ana kadar oynanan maçlardaki ev sahibi/beraberlik/deplasman oranları
+	ModelMonteCarlo = "montecarlo" // MonteCarloPredictor şampiyonluk olasılıkları
+	ModelUniform    = "uniform"    // Her takıma eşit şampiyonluk olasılığı
+)
+
+// Tahmin edilen sonuçlar
+const (
+	TargetMatch    = "match_outcome" // Bir maçın ev sahibi galibiyeti, beraberlik veya deplasman galibiyetiyle bitmesi
+	TargetChampion = "champion"      // Sezonun şampiyonu
+)
+
+// Varsayılan örnek sayıları
+const (
+	DefaultSamples    = 2000 // Bir maçın sonuç olasılıkları için simüle edilen maç sayısı
+	DefaultIterations = 2000 // Bir şampiyonluk tahmini için simüle edilen sezon sayısı
+)
+
+const (
+	calibrationBins  = 10   // Kalibrasyon eğrisinin olasılık aralığı sayısı
+	probabilityFloor = 1e-6 // Log-loss hesabında sıfır olasılıkların yerine kullanılan alt sınır
+)
+
+// matchOutcomes maç sonuçlarının sıralı etiketleri; RPS bu sırayı kullanır
+var matchOutcomes = []string{"home", "draw", "away"}
+
+// ErrNoPlayedWeeks yeniden oynatılacak tamamlanmış hafta olmadığında döner
+var ErrNoPlayedWeeks = errors.New("geriye dönük test için tamamlanmış hafta yok")
+
+// Forecast bir modelin bir sonuç için yaptığı tahmin ve gerçekleşen sonuç
+type Forecast struct {
+	Season        int       `json:"season"`             // Sezonun sırası, 1'den başlar
+	Week          uint      `json:"week"`               // Tahmin yapıldığında tamamlanmış hafta sayısı
+	Model         string    `json:"model"`              // Tahmini yapan model
+	Target        string    `json:"target"`             // match_outcome veya champion
+	MatchID       uint      `json:"match_id,omitempty"` // Tahmin edilen maç; şampiyonluk tahminlerinde 0
+	Outcomes      []string  `json:"outcomes"`           // home, draw, away veya takım adları
+	Probabilities []float64 `json:"probabilities"`      // Her sonucun olasılığı, toplamı 1
+	Observed      int       `json:"observed"`           // Gerçekleşen sonucun Outcomes içindeki sırası
+}
+
+// CalibrationBin kalibrasyon eğrisinin bir olasılık aralığı
+type CalibrationBin struct {
+	Lower         float64 `json:"lower"`          // Aralığın alt sınırı
+	Upper         float64 `json:"upper"`          // Aralığın üst sınırı
+	Count         int     `json:"count"`          // Aralığa düşen olasılık sayısı
+	MeanPredicted float64 `json:"mean_predicted"` // Aralıktaki olasılıkların ortalaması
+	ObservedRate  float64 `json:"observed_rate"`  // Aralıktaki sonuçların gerçekleşme oranı
+}
+
+// ModelReport bir modelin bir hedefteki skorları
+type ModelReport struct {
+	Model       string           `json:"model"`
+	Target      string           `json:"target"`
+	Forecasts   int              `json:"forecasts"`     // Değerlendirilen tahmin sayısı
+	Brier       float64          `json:"brier"`         // Ortalama çok sınıflı Brier skoru; düşük daha iyi
+	LogLoss     float64          `json:"log_loss"`      // Gerçekleşen sonuca verilen olasılığın ortalama negatif logaritması
+	RPS         *float64         `json:"rps,omitempty"` // Ortalama sıralı olasılık skoru; yalnızca maç sonuçları için
+	Calibration []CalibrationBin `json:"calibration"`   // Tahmin edilen ve gerçekleşen oranlar
+}
+
+// Report geriye dönük testin sonuçları
+type Report struct {
+	Seasons    int           `json:"seasons"`   // Yeniden oynatılan sezon sayısı
+	Weeks      int           `json:"weeks"`     // Yeniden oynatılan toplam hafta sayısı
+	Matches    int           `json:"matches"`   // Tahmin edilen maç sayısı
+	Seed       int64         `json:"seed"`      // Poisson örneklemesinin seed'i
+	Models     []ModelReport `json:"models"`    // Modellerin skorları
+	Forecasts  []Forecast    `json:"forecasts"` // Kaydedilen bütün tahminler
+	DurationMS int64         `json:"duration_ms"`
+}
+
+// Backtester sezonları hafta hafta yeniden oynatarak tahminleri değerlendirir
+type Backtester struct {
+	samples    int
+	iterations int
+	seed       int64
+}
+
+// NewBacktester maç başına samples örnek ve şampiyonluk tahmini başına iterations
+// sezonla çalışan bir geriye dönük test oluşturur. seed 0 ise rastgele seçilir.
+func NewBacktester(samples, iterations int, seed int64) *Backtester {
+	if samples < 1 {
+		samples = DefaultSamples
+	}
+	if iterations < 1 {
+		iterations = DefaultIterations
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano() + int64(rand.Intn(1000000))
+	}
+	return &Backtester{samples: samples, iterations: iterations, seed: seed}
+}
+
+// Run her sezonu başlangıcından itibaren yeniden oynatır. Her haftadan önce
+// modeller o haftanın maçlarını ve sezon tamamlanmışsa şampiyonu tahmin eder,
+// ardından haftanın gerçek sonuçları işlenir. Yalnızca bütün maçları oynanmış
+// ardışık haftalar yeniden oynatılır. Sezonlar kopyalanarak oynatılır, verilen
+// depolar değiştirilmez ve kadro eksiklikleri hesaba katılmaz. Takım güçleri her
+// haftadan önce, içe aktarmadaki gibi yalnızca önceki haftaların sonuçlarından
+// yeniden hesaplanır; depodaki team_stats kullanılmaz, çünkü içe aktarılan
+// sezonlarda bütün sezonun sonuçlarından hesaplanmıştır. İlk haftada ve henüz
+// maç oynamamış takımlarda bütün takımlar ortalama güçtedir. progress nil
+// değilse her haftadan sonra çağrılır.
+func (b *Backtester) Run(ctx context.Context, seasons []db.Store, progress base.ProgressFunc) (*Report, error) {
+	started := time.Now()
+
+	replays := make([]*replay, 0, len(seasons))
+	total := 0
+	for i, store := range seasons {
+		r, err := loadReplay(ctx, store, i+1)
+		if err != nil {
+			return nil, err
+		}
+		if r.weeks == 0 {
+			continue
+		}
+		replays = append(replays, r)
+		total += int(r.weeks)
+	}
+	if len(replays) == 0 {
+		return nil, ErrNoPlayedWeeks
+	}
+
+	report := &Report{Seasons: len(replays), Seed: b.seed, Forecasts: []Forecast{}}
+	scorers := []*scorer{
+		newScorer(ModelPoisson, TargetMatch, true),
+		newScorer(ModelBaseRate, TargetMatch, true),
+		newScorer(ModelMonteCarlo, TargetChampion, false),
+		newScorer(ModelUniform, TargetChampion, false),
+	}
+	record := func(forecast Forecast) {
+		for _, s := range scorers {
+			if s.model == forecast.Model && s.target == forecast.Target {
+				s.add(forecast.Probabilities, forecast.Observed)
+			}
+		}
+		report.Forecasts = append(report.Forecasts, forecast)
+	}
+
+	// Ev sahibi, beraberlik ve deplasman sayıları; bütün sezonlar boyunca birikir
+	outcomeCounts := make([]int, len(matchOutcomes))
+	done := 0
+	for _, r := range replays {
+		if err := b.replaySeason(ctx, r, outcomeCounts, record, func() {
+			done++
+			if progress != nil {
+				progress(done, total)
+			}
+		}); err != nil {
+			return nil, err
+		}
+		report.Weeks += int(r.weeks)
+		report.Matches += r.matchCount()
+	}
+
+	for _, s := range scorers {
+		if s.forecasts > 0 {
+			report.Models = append(report.Models, s.report())
+		}
+	}
+	report.DurationMS = time.Since(started).Milliseconds()
+
+	return report, nil
+}
+
+// replay yeniden oynatılacak bir sezon
+type replay struct {
+	season   int
+	teams    []models.Team
+	matches  []models.Match // Bütün fikstür, hafta ve ID sırasıyla
+	weeks    uint           // Yeniden oynatılacak ardışık tamamlanmış hafta sayısı
+	champion int            // Şampiyonun teams içindeki sırası; sezon tamamlanmamışsa -1
+}
+
+// loadReplay bir sezonun takımlarını, fikstürünü ve şampiyonunu okur
+func loadReplay(ctx context.Context, store db.Store, season int) (*replay, error) {
+	teams, err := store.Teams().ListTeams(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("takımlar alınamadı: %v", err)
+	}
+	matches, err := store.Matches().ListMatches(ctx, db.MatchFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("fikstür alınamadı: %v", err)
+	}
+
+	r := &replay{season: season, teams: teams, matches: matches, champion: -1}
+	if len(teams) < 2 || len(matches) == 0 {
+		return r, nil
+	}
+
+	// Bir maçı oynanmamış ilk haftadan önceki haftalar yeniden oynatılır
+	lastWeek := matches[len(matches)-1].Week
+	r.weeks = lastWeek
+	complete := true
+	for _, match := range matches {
+		if match.HomeGoals == nil || match.AwayGoals == nil {
+			r.weeks = min(r.weeks, match.Week-1)
+			complete = false
+		}
+	}
+
+	if complete {
+		standings, err := db.CalculateStandings(ctx, store)
+		if err != nil {
+			return nil, fmt.Errorf("puan durumu hesaplanamadı: %v", err)
+		}
+		for i, team := range teams {
+			if len(standings) > 0 && team.ID == standings[0].TeamID {
+				r.champion = i
+			}
+		}
+	}
+	return r, nil
+}
+
+// matchCount yeniden oynatılan haftalardaki maç sayısı
+func (r *replay) matchCount() int {
+	count := 0
+	for _, match := range r.matches {
+		if match.Week <= r.weeks {
+			count++
+		}
+	}
+	return count
+}
+
+// replaySeason bir sezonu kopya bir depoda hafta hafta oynatır ve tahminleri kaydeder
+func (b *Backtester) replaySeason(ctx context.Context, r *replay, outcomeCounts []int, record func(Forecast), weekDone func()) error {
+	// Şampiyonluk tahmini yalnızca oynanmış maçları okuyabildiği için sezon, sonuçları
+	// hafta hafta işlenen boş bir fikstürle bellekte yeniden kurulur
+	scratch := db.NewMemoryStore()
+	teamIDs := make(map[uint]uint, len(r.teams)) // Asıl takım ID -> kopya takım ID
+	teamNames := make([]string, len(r.teams))
+	for i, team := range r.teams {
+		copied := models.Team{Name: team.Name, Attack: team.Attack, Defense: team.Defense}
+		if err := scratch.Teams().CreateTeam(ctx, &copied); err != nil {
+			return err
+		}
+		teamIDs[team.ID] = copied.ID
+		teamNames[i] = team.Name
+	}
+	fixtures := make([]models.Match, len(r.matches))
+	for i, match := range r.matches {
+		fixtures[i] = models.Match{Week: match.Week, HomeTeamID: teamIDs[match.HomeTeamID], AwayTeamID: teamIDs[match.AwayTeamID]}
+	}
+	if err := scratch.Matches().CreateMatches(ctx, fixtures); err != nil {
+		return err
+	}
+
+	sim := poisson.NewSeededPoissonSimulator(scratch, b.seed+int64(r.season))
+	full := squad.FullStrength()
+	uniform := make([]float64, len(r.teams))
+	for i := range uniform {
+		uniform[i] = 1 / float64(len(r.teams))
+	}
+
+	// Takımların bu haftaya kadarki golleri; asıl takım ID'sine göre
+	tallies := make(map[uint]*simModels.GoalTally, len(r.teams))
+	for _, team := range r.teams {
+		tallies[team.ID] = &simModels.GoalTally{}
+	}
+	var goals, played uint
+
+	for week := uint(0); week < r.weeks; week++ {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("geriye dönük test durduruldu: %w", err)
+		}
+
+		// Güçleri yalnızca tamamlanmış haftaların sonuçlarından yeniden hesapla
+		average := simModels.AverageGoals(goals, played)
+		stats := make(map[uint]*simModels.TeamStats, len(r.teams))
+		for _, team := range r.teams {
+			fitted := simModels.FitStats(*tallies[team.ID], average)
+			fitted.TeamID = teamIDs[team.ID]
+			stats[team.ID] = &fitted
+			if err := scratch.Teams().SaveStats(ctx, &models.TeamStats{
+				TeamID:          fitted.TeamID,
+				AvgScored:       fitted.AvgScored,
+				AvgConceded:     fitted.AvgConceded,
+				AttackStrength:  fitted.AttackStrength,
+				DefenseStrength: fitted.DefenseStrength,
+			}); err != nil {
+				return err
+			}
+		}
+
+		// Sezon tamamlanmışsa bu haftaya kadarki sonuçlarla şampiyonu tahmin et
+		if r.champion >= 0 {
+			predictor := montecarlo.NewMonteCarloPredictor(scratch, b.iterations)
+			probabilities, err := predictor.PredictChampionshipProbabilities(ctx, week, nil)
+			if err != nil {
+				return fmt.Errorf("%d. sezon %d. hafta şampiyonluk tahmini yapılamadı: %w", r.season, week, err)
+			}
+			predicted := make([]float64, len(r.teams))
+			for i, team := range r.teams {
+				predicted[i] = probabilities[teamIDs[team.ID]] / 100
+			}
+			record(Forecast{Season: r.season, Week: week, Model: ModelMonteCarlo, Target: TargetChampion, Outcomes: teamNames, Probabilities: predicted, Observed: r.champion})
+			record(Forecast{Season: r.season, Week: week, Model: ModelUniform, Target: TargetChampion, Outcomes: teamNames, Probabilities: uniform, Observed: r.champion})
+		}
+
+		// Haftanın maçlarını önce tahmin et, sonra gerçek sonuçları işle
+		baseRate := smoothedRates(outcomeCounts)
+		var observed []int
+		for i, match := range r.matches {
+			if match.Week != week+1 {
+				continue
+			}
+			outcome := matchOutcome(int(*match.HomeGoals), int(*match.AwayGoals))
+			observed = append(observed, outcome)
+
+			predicted := b.sampleOutcomes(sim, stats[match.HomeTeamID], stats[match.AwayTeamID], full)
+			record(Forecast{Season: r.season, Week: week, Model: ModelPoisson, Target: TargetMatch, MatchID: match.ID, Outcomes: matchOutcomes, Probabilities: predicted, Observed: outcome})
+			record(Forecast{Season: r.season, Week: week, Model: ModelBaseRate, Target: TargetMatch, MatchID: match.ID, Outcomes: matchOutcomes, Probabilities: baseRate, Observed: outcome})
+
+			result := fixtures[i]
+			result.HomeGoals, result.AwayGoals, result.PlayedAt = match.HomeGoals, match.AwayGoals, match.PlayedAt
+			if err := scratch.Matches().SaveResult(ctx, &result); err != nil {
+				return err
+			}
+			tallies[match.HomeTeamID].Add(*match.HomeGoals, *match.AwayGoals)
+			tallies[match.AwayTeamID].Add(*match.AwayGoals, *match.HomeGoals)
+			goals += *match.HomeGoals + *match.AwayGoals
+			played++
+		}
+		for _, outcome := range observed {
+			outcomeCounts[outcome]++
+		}
+		weekDone()
+	}
+	return nil
+}
+
+// sampleOutcomes bir maçı Poisson modeliyle samples kez oynatarak ev sahibi
+// galibiyeti, beraberlik ve deplasman galibiyeti olasılıklarını tahmin eder
+func (b *Backtester) sampleOutcomes(sim *poisson.PoissonSimulator, home, away *simModels.TeamStats, full squad.Factors) []float64 {
+	counts := make([]int, len(matchOutcomes))
+	for i := 0; i < b.samples; i++ {
+		homeLambda, awayLambda := sim.MatchLambdas(home, away, full, full)
+		counts[matchOutcome(sim.GenerateGoals(homeLambda), sim.GenerateGoals(awayLambda))]++
+	}
+	probabilities := make([]float64, len(counts))
+	for i, count := range counts {
+		probabilities[i] = float64(count) / float64(b.samples)
+	}
+	return probabilities
+}
+
+// smoothedRates sonuç sayılarını Laplace düzeltmesiyle olasılıklara çevirir;
+// hiç maç oynanmamışsa her sonuca eşit olasılık verir
+func smoothedRates(counts []int) []float64 {
+	total := len(counts)
+	for _, count := range counts {
+		total += count
+	}
+	rates := make([]float64, len(counts))
+	for i, count := range counts {
+		rates[i] = float64(count+1) / float64(total)
+	}
+	return rates
+}
+
+// matchOutcome skorun matchOutcomes içindeki sırasını döndürür
+func matchOutcome(homeGoals, awayGoals int) int {
+	switch {
+	case homeGoals > awayGoals:
+		return 0
+	case homeGoals == awayGoals:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,92 @@
+package backtest
+
+import "math"
+
+// scorer bir modelin tahminlerinden skorları ve kalibrasyon eğrisini biriktirir
+type scorer struct {
+	model     string
+	target    string
+	ordered   bool // Sonuçlar sıralıysa RPS de hesaplanır
+	forecasts int
+	brier     float64
+	logLoss   float64
+	rps       float64
+	bins      [calibrationBins]struct {
+		count     int
+		predicted float64
+		observed  int
+	}
+}
+
+// newScorer bir model ve hedef için boş bir skor tablosu oluşturur
+func newScorer(model, target string, ordered bool) *scorer {
+	return &scorer{model: model, target: target, ordered: ordered}
+}
+
+// add bir tahmini skorlara ekler; observed gerçekleşen sonucun sırasıdır
+func (s *scorer) add(probabilities []float64, observed int) {
+	s.forecasts++
+
+	// Brier: her sonucun olasılığıyla gerçekleşip gerçekleşmemesi arasındaki farkların karesi
+	for i, p := range probabilities {
+		hit := 0.0
+		if i == observed {
+			hit = 1
+		}
+		s.brier += (p - hit) * (p - hit)
+
+		bin := &s.bins[min(int(p*calibrationBins), calibrationBins-1)]
+		bin.count++
+		bin.predicted += p
+		if i == observed {
+			bin.observed++
+		}
+	}
+
+	s.logLoss -= math.Log(max(probabilities[observed], probabilityFloor))
+
+	// RPS: birikimli olasılıklarla birikimli sonuçlar arasındaki farkların karesi
+	if s.ordered && len(probabilities) > 1 {
+		var cumulative, sum float64
+		for i := 0; i < len(probabilities)-1; i++ {
+			cumulative += probabilities[i]
+			hit := 0.0
+			if observed <= i {
+				hit = 1
+			}
+			sum += (cumulative - hit) * (cumulative - hit)
+		}
+		s.rps += sum / float64(len(probabilities)-1)
+	}
+}
+
+// report biriken skorların ortalamalarını ve kalibrasyon eğrisini döndürür
+func (s *scorer) report() ModelReport {
+	n := float64(s.forecasts)
+	report := ModelReport{
+		Model:       s.model,
+		Target:      s.target,
+		Forecasts:   s.forecasts,
+		Brier:       s.brier / n,
+		LogLoss:     s.logLoss / n,
+		Calibration: make([]CalibrationBin, calibrationBins),
+	}
+	if s.ordered {
+		rps := s.rps / n
+		report.RPS = &rps
+	}
+
+	for i, bin := range s.bins {
+		calibration := CalibrationBin{
+			Lower: float64(i) / calibrationBins,
+			Upper: float64(i+1) / calibrationBins,
+			Count: bin.count,
+		}
+		if bin.count > 0 {
+			calibration.MeanPredicted = bin.predicted / float64(bin.count)
+			calibration.ObservedRate = float64(bin.observed) / float64(bin.count)
+		}
+		report.Calibration[i] = calibration
+	}
+	return report
+}
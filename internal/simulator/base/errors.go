@@ -0,0 +1,40 @@
+package base
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Simulation errors
+// Errors for records that do not exist (teams, matches) are defined in the db package.
+var (
+	ErrSeasonComplete     = errors.New("sezon tamamlandı, oynanmamış maç bulunamadı")
+	ErrMatchAlreadyPlayed = errors.New("maç zaten oynanmış")
+)
+
+// WeekSimulationError is returned when one or more matches of a week could not be
+// simulated or saved. The whole week is rolled back when it is returned.
+type WeekSimulationError struct {
+	Week           uint           // Week that failed
+	FailedMatchIDs []uint         // IDs of the matches that failed, in fixture order
+	Errors         map[uint]error // Underlying error of each failed match
+}
+
+// Error implements the error interface
+func (e *WeekSimulationError) Error() string {
+	ids := make([]string, len(e.FailedMatchIDs))
+	for i, id := range e.FailedMatchIDs {
+		ids[i] = fmt.Sprintf("%d", id)
+	}
+	return fmt.Sprintf("%d. hafta simüle edilemedi, başarısız maçlar: %s", e.Week, strings.Join(ids, ", "))
+}
+
+// Unwrap returns the underlying errors of the failed matches
+func (e *WeekSimulationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.FailedMatchIDs))
+	for _, id := range e.FailedMatchIDs {
+		errs = append(errs, e.Errors[id])
+	}
+	return errs
+}
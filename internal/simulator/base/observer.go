@@ -0,0 +1,50 @@
+package base
+
+import (
+	simModels "github.com/tarikbacak/insider-league-simulator/internal/simulator/models"
+)
+
+// ResultObserver is notified after match results have been committed
+// week is the week the results belong to; results holds every match saved for it.
+type ResultObserver func(week uint, results []simModels.MatchResult)
+
+// PredictionObserver is notified after championship predictions have been saved
+// probabilities maps team IDs to their championship probability in percent.
+type PredictionObserver func(week uint, probabilities map[uint]float64)
+
+// Observers are the callbacks a simulator or predictor notifies
+// They are given to each simulator and predictor when it is created, so only
+// the league they play reaches them; the zero value notifies nobody.
+type Observers struct {
+	Results     []ResultObserver
+	Predictions []PredictionObserver
+}
+
+// Merge returns the observers of o followed by those of other
+func (o Observers) Merge(other Observers) Observers {
+	return Observers{
+		Results:     append(append([]ResultObserver(nil), o.Results...), other.Results...),
+		Predictions: append(append([]PredictionObserver(nil), o.Predictions...), other.Predictions...),
+	}
+}
+
+// NotifyResults calls every result observer with committed results
+// Simulators must only call it after the transaction saving the results has committed.
+func (o Observers) NotifyResults(week uint, results []simModels.MatchResult) {
+	if len(results) == 0 {
+		return
+	}
+	for _, observer := range o.Results {
+		observer(week, results)
+	}
+}
+
+// NotifyPredictions calls every prediction observer with saved predictions
+func (o Observers) NotifyPredictions(week uint, probabilities map[uint]float64) {
+	if len(probabilities) == 0 {
+		return
+	}
+	for _, observer := range o.Predictions {
+		observer(week, probabilities)
+	}
+}
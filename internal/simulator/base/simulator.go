@@ -1,14 +1,28 @@
 // Package base contains the core simulator interfaces and structures
 package base
 
+import (
+	"context"
+
+	simModels "github.com/tarikbacak/insider-league-simulator/internal/simulator/models"
+)
+
+// ProgressFunc is called by long-running operations with the number of steps
+// completed so far and the total number of steps
+type ProgressFunc func(done, total int)
+
 // Simulator is the core interface for match simulation
+// Every method runs its database queries with ctx and stops when it is cancelled.
 type Simulator interface {
-	SimulateMatch(homeTeamID, awayTeamID uint) (homeGoals, awayGoals int, err error)
-	PlayNextWeek() error
-	PlayAllRemainingWeeks() error
+	SimulateMatch(ctx context.Context, homeTeamID, awayTeamID uint) (homeGoals, awayGoals int, err error)
+	PlayMatch(ctx context.Context, matchID uint, force bool) (*simModels.MatchResult, error)
+	PlayNextWeek(ctx context.Context) ([]simModels.MatchResult, error)
+	PlayAllRemainingWeeks(ctx context.Context, progress ProgressFunc) ([]simModels.MatchResult, error)
+	PlayUntilWeek(ctx context.Context, week uint) ([]simModels.MatchResult, error)
 }
 
 // Predictor is the core interface for championship prediction
+// The prediction stops when ctx is cancelled; progress may be nil.
 type Predictor interface {
-	PredictChampionshipProbabilities(week uint) (map[uint]float64, error)
+	PredictChampionshipProbabilities(ctx context.Context, week uint, progress ProgressFunc) (map[uint]float64, error)
 }
@@ -0,0 +1,25 @@
+package models
+
+// MatchResult holds the outcome of a simulated match and the lambdas used to generate it
+type MatchResult struct {
+	MatchID    uint    `json:"match_id"`
+	Week       uint    `json:"week"`
+	HomeTeamID uint    `json:"home_team_id"`
+	AwayTeamID uint    `json:"away_team_id"`
+	HomeGoals  int     `json:"home_goals"`
+	AwayGoals  int     `json:"away_goals"`
+	HomeLambda float64 `json:"home_lambda"`        // Expected goals of the home team (λ_home)
+	AwayLambda float64 `json:"away_lambda"`        // Expected goals of the away team (λ_away)
+	Replayed   bool    `json:"replayed,omitempty"` // The match had already been played and was simulated again
+}
+
+// HasFirstPlays reports whether any of results is the first result of its
+// match, i.e. the results changed which matches are played
+func HasFirstPlays(results []MatchResult) bool {
+	for _, result := range results {
+		if !result.Replayed {
+			return true
+		}
+	}
+	return false
+}
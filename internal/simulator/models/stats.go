@@ -12,6 +12,48 @@ type TeamStats struct {
 
 // Constants for simulation
 const (
-	LeagueAverage = 1.5 // League-wide average number of goals (λ_league)
-	MinLambda     = 0.1 // Minimum value for lambda
+	LeagueAverage = 1.5  // League-wide average number of goals (λ_league)
+	MinLambda     = 0.1  // Minimum value for lambda
+	MaxLambda     = 4.0  // Maximum value for lambda
+	HomeAdvantage = 0.15 // Largest random home advantage added to the home lambda (0-15%)
+	FormFactorMin = 0.8  // Smallest random form factor of a team on match day
+	FormFactorMax = 1.2  // Largest random form factor of a team on match day
+	MaxGoals      = 8    // Most goals a team can score in a match
 )
+
+// GoalTally holds the goals of a team in its played matches
+type GoalTally struct {
+	Played   uint // Played matches
+	Scored   uint // Goals scored
+	Conceded uint // Goals conceded
+}
+
+// Add counts a played match with the given goals for and against
+func (t *GoalTally) Add(scored, conceded uint) {
+	t.Played++
+	t.Scored += scored
+	t.Conceded += conceded
+}
+
+// AverageGoals returns the goals per team per match of played matches with
+// goals in total, or LeagueAverage if no goal has been scored yet
+func AverageGoals(goals, played uint) float64 {
+	if played == 0 || goals == 0 {
+		return LeagueAverage
+	}
+	return float64(goals) / float64(2*played)
+}
+
+// FitStats fits the statistics of a team from its tally: the goals it scored
+// and conceded per match relative to average. A team without a played match
+// gets average statistics and strength 1.
+func FitStats(t GoalTally, average float64) TeamStats {
+	stats := TeamStats{AvgScored: average, AvgConceded: average, AttackStrength: 1, DefenseStrength: 1}
+	if t.Played > 0 {
+		stats.AvgScored = float64(t.Scored) / float64(t.Played)
+		stats.AvgConceded = float64(t.Conceded) / float64(t.Played)
+		stats.AttackStrength = max(stats.AvgScored/average, MinLambda)
+		stats.DefenseStrength = max(stats.AvgConceded/average, MinLambda)
+	}
+	return stats
+}
@@ -2,22 +2,33 @@
 package montecarlo
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
 
 	"github.com/tarikbacak/insider-league-simulator/internal/db"
 	"github.com/tarikbacak/insider-league-simulator/internal/models"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/base"
 	"github.com/tarikbacak/insider-league-simulator/internal/simulator/poisson"
-	"gorm.io/gorm"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/squad"
+)
+
+// Bir tahminde simüle edilen sezon sayısının sınırları; bu aralığın dışındaki
+// değerler en yakın sınıra çekilir
+const (
+	MinIterations = 1000 // Doğruluk için en az
+	MaxIterations = 5000 // Hız için en çok
 )
 
 // MonteCarloPredictor Monte Carlo simülasyonu ile şampiyonluk tahmini yapar
 type MonteCarloPredictor struct {
-	db         *gorm.DB
+	store      db.Store
 	simulator  *poisson.PoissonSimulator
 	iterations int
 	teamStats  map[uint]*TeamStats // Cache for team stats
+	observers  base.Observers      // Kaydedilen tahminleri bildirilen gözlemciler
 }
 
 // TeamStats team statistics cache
@@ -26,42 +37,67 @@ type TeamStats struct {
 	DefenseStrength float64
 }
 
-// NewMonteCarloPredictor yeni bir Monte Carlo tahmin edici oluşturur
-func NewMonteCarloPredictor(iterations int) *MonteCarloPredictor {
-	// Hızlı tahminler için iterasyon sayısını azalt
-	if iterations > 5000 {
-		iterations = 5000 // Maximum 5000 iteration for speed
-	}
-	if iterations < 1000 {
-		iterations = 1000 // Minimum 1000 for accuracy
-	}
+// fixture kalan bir maçı, takımların o maçtaki kadro durumuyla birlikte tutar
+type fixture struct {
+	HomeTeamID  uint
+	AwayTeamID  uint
+	HomeFactors squad.Factors
+	AwayFactors squad.Factors
+}
+
+// NewMonteCarloPredictor ligi verilen depodan okuyan yeni bir Monte Carlo tahmin edici oluşturur
+func NewMonteCarloPredictor(store db.Store, iterations int) *MonteCarloPredictor {
+	// Hızlı ve yeterince doğru tahminler için iterasyon sayısını sınırla
+	iterations = min(max(iterations, MinIterations), MaxIterations)
 
 	return &MonteCarloPredictor{
-		db:         db.GetDB(),
-		simulator:  poisson.NewPoissonSimulator(),
+		store:      store,
+		simulator:  poisson.NewPoissonSimulator(store),
 		iterations: iterations,
 		teamStats:  make(map[uint]*TeamStats),
 	}
 }
 
-// PredictChampionshipProbabilities belirtilen hafta için şampiyonluk olasılıklarını hesaplar
-func (mcp *MonteCarloPredictor) PredictChampionshipProbabilities(week uint) (map[uint]float64, error) { // Cache team stats once for all iterations
-	if err := mcp.loadTeamStats(); err != nil {
+// WithObservers kaydedilen tahminleri verilen gözlemcilere bildirir ve tahmin
+// edicinin kendisini döndürür
+func (mcp *MonteCarloPredictor) WithObservers(observers base.Observers) *MonteCarloPredictor {
+	mcp.observers = observers
+	return mcp
+}
+
+// PredictChampionshipProbabilities belirtilen hafta için şampiyonluk olasılıklarını
+// hesaplar. Sorgular ctx ile yapılır ve ctx her iterasyon grubundan önce kontrol
+// edilir; iptal edilirse tahminler kaydedilmeden hata döner. progress nil değilse
+// her gruptan sonra tamamlanan ve toplam iterasyon sayısıyla çağrılır. Oynanacak
+// maç kalmamışsa base.ErrSeasonComplete döner.
+func (mcp *MonteCarloPredictor) PredictChampionshipProbabilities(ctx context.Context, week uint, progress base.ProgressFunc) (map[uint]float64, error) {
+	teams, err := mcp.store.Teams().ListTeams(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("takımlar alınamadı: %v", err)
+	}
+
+	// Cache team stats once for all iterations
+	if err := mcp.loadTeamStats(ctx, teams); err != nil {
 		return nil, fmt.Errorf("takım istatistikleri yüklenemedi: %v", err)
 	}
 
-	currentStandings, err := mcp.getCurrentStandings()
+	currentStandings, err := mcp.getCurrentStandings(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("mevcut puan durumu alınamadı: %v", err)
 	}
 
-	remainingMatches, err := mcp.getRemainingMatches()
+	remainingMatches, err := mcp.getRemainingMatches(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("kalan maçlar alınamadı: %v", err)
 	}
 
 	if len(remainingMatches) == 0 {
-		return nil, fmt.Errorf("tüm maçlar tamamlanmış, tahmin yapılamaz")
+		return nil, fmt.Errorf("%w: tahmin yapılamaz", base.ErrSeasonComplete)
+	}
+
+	fixtures, err := mcp.buildFixtures(ctx, remainingMatches)
+	if err != nil {
+		return nil, fmt.Errorf("kadro durumları alınamadı: %v", err)
 	}
 
 	log.Printf("Monte Carlo simülasyonu başlatılıyor: %d iterasyon, %d kalan maç", mcp.iterations, len(remainingMatches))
@@ -71,6 +107,10 @@ func (mcp *MonteCarloPredictor) PredictChampionshipProbabilities(week uint) (map
 	// Batch process iterations for speed
 	batchSize := 100
 	for batch := 0; batch < mcp.iterations; batch += batchSize {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("tahmin durduruldu: %w", err)
+		}
+
 		currentBatchSize := batchSize
 		if batch+batchSize > mcp.iterations {
 			currentBatchSize = mcp.iterations - batch
@@ -79,64 +119,105 @@ func (mcp *MonteCarloPredictor) PredictChampionshipProbabilities(week uint) (map
 		for i := 0; i < currentBatchSize; i++ {
 			standings := mcp.copyStandings(currentStandings)
 			// Kalan maçları hızlı simüle et
-			for _, match := range remainingMatches {
-				homeGoals, awayGoals := mcp.fastSimulateMatch(match.HomeTeamID, match.AwayTeamID)
-				mcp.updateStandings(standings, match.HomeTeamID, match.AwayTeamID, homeGoals, awayGoals)
+			for _, f := range fixtures {
+				homeGoals, awayGoals := mcp.fastSimulateMatch(f)
+				mcp.updateStandings(standings, f.HomeTeamID, f.AwayTeamID, homeGoals, awayGoals)
 			}
 
 			championID := mcp.findChampion(standings)
 			championCounts[championID]++
 		}
+
+		if progress != nil {
+			progress(batch+currentBatchSize, mcp.iterations)
+		}
 	}
 
 	// Olasılıkları hesapla
-	probabilities := mcp.calculateProbabilities(championCounts)
+	probabilities := mcp.calculateProbabilities(teams, championCounts)
 
 	// Tahminleri kaydet
-	if err := mcp.savePredictions(week, probabilities); err != nil {
+	if err := mcp.store.Predictions().SavePredictions(ctx, week, probabilities); err != nil {
 		log.Printf("Tahminler kaydedilirken hata: %v", err)
+	} else {
+		mcp.observers.NotifyPredictions(week, probabilities)
 	}
 
 	return probabilities, nil
 }
 
 // getCurrentStandings mevcut puan durumunu döndürür
-func (mcp *MonteCarloPredictor) getCurrentStandings() (map[uint]int, error) {
-	var teams []struct {
-		ID     uint
-		Points int
-	}
-
-	err := mcp.db.Model(&models.Team{}).
-		Select("teams.id, COALESCE(SUM(CASE " +
-			"WHEN matches.home_team_id = teams.id AND matches.home_goals > matches.away_goals THEN 3 " +
-			"WHEN matches.away_team_id = teams.id AND matches.away_goals > matches.home_goals THEN 3 " +
-			"WHEN matches.home_goals = matches.away_goals THEN 1 " +
-			"ELSE 0 END), 0) as points").
-		Joins("LEFT JOIN matches ON (teams.id = matches.home_team_id OR teams.id = matches.away_team_id) " +
-			"AND matches.home_goals IS NOT NULL AND matches.away_goals IS NOT NULL").
-		Group("teams.id").
-		Scan(&teams).Error
-
+func (mcp *MonteCarloPredictor) getCurrentStandings(ctx context.Context) (map[uint]int, error) {
+	table, err := db.CalculateStandings(ctx, mcp.store)
 	if err != nil {
 		return nil, err
 	}
 
 	standings := make(map[uint]int)
-	for _, t := range teams {
-		standings[t.ID] = t.Points
+	for _, t := range table {
+		standings[t.TeamID] = int(t.Points)
 	}
 
 	return standings, nil
 }
 
 // getRemainingMatches oynanmamış maçları döndürür
-func (mcp *MonteCarloPredictor) getRemainingMatches() ([]models.Match, error) {
-	var matches []models.Match
-	err := mcp.db.Where("home_goals IS NULL AND away_goals IS NULL").
-		Order("week, id").
-		Find(&matches).Error
-	return matches, err
+func (mcp *MonteCarloPredictor) getRemainingMatches(ctx context.Context) ([]models.Match, error) {
+	return mcp.store.Matches().ListMatches(ctx, db.MatchFilter{Played: db.Played(false)})
+}
+
+// buildFixtures kalan maçları, her takımın o maçta hâlâ forma giyemeyecek
+// oyuncularına göre hesaplanan güç çarpanlarıyla birlikte hazırlar
+func (mcp *MonteCarloPredictor) buildFixtures(ctx context.Context, matches []models.Match) ([]fixture, error) {
+	type teamSquad struct {
+		players   []models.Player
+		remaining map[uint]uint // Oyuncu ID -> kaçıracağı kalan maç sayısı
+		ahead     uint          // Takımın bu maça kadar oynayacağı maç sayısı
+	}
+	squads := make(map[uint]*teamSquad)
+
+	factorsFor := func(teamID uint) (squad.Factors, error) {
+		ts, ok := squads[teamID]
+		if !ok {
+			players, err := mcp.simulator.GetSquad(ctx, teamID)
+			if err != nil {
+				return squad.FullStrength(), err
+			}
+			remaining, err := mcp.simulator.GetUnavailablePlayers(ctx, teamID)
+			if err != nil {
+				return squad.FullStrength(), err
+			}
+			ts = &teamSquad{players: players, remaining: remaining}
+			squads[teamID] = ts
+		}
+
+		factors := squad.FullStrength()
+		if len(ts.remaining) > 0 {
+			factors = squad.StrengthFactors(ts.players, squad.Unavailable(ts.remaining, ts.ahead))
+		}
+		ts.ahead++
+		return factors, nil
+	}
+
+	fixtures := make([]fixture, 0, len(matches))
+	for _, match := range matches {
+		homeFactors, err := factorsFor(match.HomeTeamID)
+		if err != nil {
+			return nil, err
+		}
+		awayFactors, err := factorsFor(match.AwayTeamID)
+		if err != nil {
+			return nil, err
+		}
+		fixtures = append(fixtures, fixture{
+			HomeTeamID:  match.HomeTeamID,
+			AwayTeamID:  match.AwayTeamID,
+			HomeFactors: homeFactors,
+			AwayFactors: awayFactors,
+		})
+	}
+
+	return fixtures, nil
 }
 
 // copyStandings puan durumunun bir kopyasını oluşturur
@@ -174,16 +255,13 @@ func (mcp *MonteCarloPredictor) findChampion(standings map[uint]int) uint {
 }
 
 // calculateProbabilities şampiyonluk olasılıklarını hesaplar
-func (mcp *MonteCarloPredictor) calculateProbabilities(championCounts map[uint]int) map[uint]float64 {
+func (mcp *MonteCarloPredictor) calculateProbabilities(teams []models.Team, championCounts map[uint]int) map[uint]float64 {
 	probabilities := make(map[uint]float64)
 	totalIterations := float64(mcp.iterations)
 
 	// İlk önce tüm takımları 0% ile başlat
-	var teams []models.Team
-	if err := mcp.db.Find(&teams).Error; err == nil {
-		for _, team := range teams {
-			probabilities[team.ID] = 0.0
-		}
+	for _, team := range teams {
+		probabilities[team.ID] = 0.0
 	}
 
 	// Gerçek olasılıkları hesapla
@@ -208,39 +286,16 @@ func (mcp *MonteCarloPredictor) calculateProbabilities(championCounts map[uint]i
 	return probabilities
 }
 
-// savePredictions tahminleri veritabanına kaydeder
-func (mcp *MonteCarloPredictor) savePredictions(week uint, probabilities map[uint]float64) error {
-	return mcp.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Delete(&models.Prediction{}, "week = ?", week).Error; err != nil {
-			return err
-		}
-		for teamID, probability := range probabilities {
-			prediction := models.Prediction{
-				Week:        week,
-				TeamID:      teamID,
-				Probability: probability,
-			}
-			if err := tx.Create(&prediction).Error; err != nil {
-				return err
-			}
-		}
-
-		return nil
-	})
-}
-
 // loadTeamStats loads and caches team statistics for fast access
-func (mcp *MonteCarloPredictor) loadTeamStats() error {
-	var teams []models.Team
-	if err := mcp.db.Find(&teams).Error; err != nil {
-		return err
-	}
-
+func (mcp *MonteCarloPredictor) loadTeamStats(ctx context.Context, teams []models.Team) error {
 	for _, team := range teams {
-		var stats models.TeamStats
-		if err := mcp.db.Where("team_id = ?", team.ID).First(&stats).Error; err != nil {
+		stats, err := mcp.store.Teams().GetStats(ctx, team.ID)
+		if errors.Is(err, db.ErrTeamNotFound) {
 			continue
 		}
+		if err != nil {
+			return err
+		}
 
 		mcp.teamStats[team.ID] = &TeamStats{
 			AttackStrength:  stats.AttackStrength,
@@ -252,19 +307,25 @@ func (mcp *MonteCarloPredictor) loadTeamStats() error {
 }
 
 // fastSimulateMatch performs fast match simulation without database access
-func (mcp *MonteCarloPredictor) fastSimulateMatch(homeTeamID, awayTeamID uint) (homeGoals, awayGoals int) {
-	homeStats, homeExists := mcp.teamStats[homeTeamID]
-	awayStats, awayExists := mcp.teamStats[awayTeamID]
+// Squad availability factors weaken attack and defense like in PoissonSimulator
+func (mcp *MonteCarloPredictor) fastSimulateMatch(f fixture) (homeGoals, awayGoals int) {
+	homeStats, homeExists := mcp.teamStats[f.HomeTeamID]
+	awayStats, awayExists := mcp.teamStats[f.AwayTeamID]
 
 	if !homeExists || !awayExists {
 		// Fallback to simple random
 		return mcp.simpleRandomScore(), mcp.simpleRandomScore()
 	}
 
+	homeAttack := homeStats.AttackStrength * f.HomeFactors.Attack
+	homeDefense := homeStats.DefenseStrength / f.HomeFactors.Defense
+	awayAttack := awayStats.AttackStrength * f.AwayFactors.Attack
+	awayDefense := awayStats.DefenseStrength / f.AwayFactors.Defense
+
 	// Simplified lambda calculation (no database access)
-	leagueAvg := 1.5                                                                     // Average goals per team per match
-	homeLambda := homeStats.AttackStrength * awayStats.DefenseStrength * leagueAvg * 1.1 // Home advantage
-	awayLambda := awayStats.AttackStrength * homeStats.DefenseStrength * leagueAvg
+	leagueAvg := 1.5                                         // Average goals per team per match
+	homeLambda := homeAttack * awayDefense * leagueAvg * 1.1 // Home advantage
+	awayLambda := awayAttack * homeDefense * leagueAvg
 
 	// Fast Poisson approximation
 	homeGoals = mcp.fastPoisson(homeLambda)
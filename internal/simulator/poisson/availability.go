@@ -0,0 +1,122 @@
+package poisson
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/squad"
+)
+
+// Sakatlık ve ceza parametreleri
+const (
+	maxInjuryMatches = 3 // Bir sakatlığın kaçırtabileceği maksimum maç sayısı
+	yellowCardLimit  = 3 // Cezaya yol açan sarı kart birikimi
+)
+
+// GetUnavailablePlayers takımın sakat veya cezalı oyuncularını, kaçıracakları
+// kalan maç sayısıyla birlikte döndürür
+func (ps *PoissonSimulator) GetUnavailablePlayers(ctx context.Context, teamID uint) (map[uint]uint, error) {
+	remaining, err := ps.store.Teams().UnavailablePlayers(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("oyuncu eksikleri alınamadı (ID: %d): %v", teamID, err)
+	}
+	return remaining, nil
+}
+
+// GetAvailableSquad takımın bir sonraki maçta forma giyebilecek oyuncularını döndürür
+func (ps *PoissonSimulator) GetAvailableSquad(ctx context.Context, teamID uint) ([]models.Player, error) {
+	players, err := ps.GetSquad(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining, err := ps.GetUnavailablePlayers(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	return squad.Available(players, squad.Unavailable(remaining, 0)), nil
+}
+
+// GetAvailabilityFactors takımın bir sonraki maçı için kadro durumuna bağlı güç çarpanlarını hesaplar
+func (ps *PoissonSimulator) GetAvailabilityFactors(ctx context.Context, teamID uint) (squad.Factors, error) {
+	players, err := ps.GetSquad(ctx, teamID)
+	if err != nil {
+		return squad.FullStrength(), err
+	}
+
+	remaining, err := ps.GetUnavailablePlayers(ctx, teamID)
+	if err != nil {
+		return squad.FullStrength(), err
+	}
+
+	return squad.StrengthFactors(players, squad.Unavailable(remaining, 0)), nil
+}
+
+// recordAbsences maçta yaşanan sakatlıkları ve kartlardan doğan cezaları kaydeder.
+// Direkt kırmızı kart, aynı maçta iki sarı kart ve her yellowCardLimit sarı kart
+// birikimi birer maç cezaya yol açar.
+func (ps *PoissonSimulator) recordAbsences(ctx context.Context, match models.Match, events []models.MatchEvent) error {
+	var absences []models.PlayerAbsence
+	newAbsence := func(playerID, teamID uint, reason string, matches uint) models.PlayerAbsence {
+		return models.PlayerAbsence{
+			PlayerID: playerID,
+			TeamID:   teamID,
+			MatchID:  match.ID,
+			Week:     match.Week,
+			Reason:   reason,
+			Matches:  matches,
+		}
+	}
+
+	// Olay sırasını koruyarak kart gören oyuncuları topla
+	var booked []uint
+	teams := make(map[uint]uint)
+	yellows := make(map[uint]int)
+	reds := make(map[uint]bool)
+	for _, event := range events {
+		if event.PlayerID == nil || event.TeamID == nil {
+			continue
+		}
+		playerID := *event.PlayerID
+
+		switch event.Type {
+		case models.EventInjury:
+			matches := 1 + uint(ps.rng.Intn(maxInjuryMatches))
+			absences = append(absences, newAbsence(playerID, *event.TeamID, models.AbsenceInjury, matches))
+		case models.EventYellowCard, models.EventRedCard:
+			if _, seen := teams[playerID]; !seen {
+				booked = append(booked, playerID)
+				teams[playerID] = *event.TeamID
+			}
+			if event.Type == models.EventYellowCard {
+				yellows[playerID]++
+			} else {
+				reds[playerID] = true
+			}
+		}
+	}
+
+	for _, playerID := range booked {
+		var matches uint
+		if reds[playerID] || yellows[playerID] >= 2 {
+			matches++
+		}
+		if yellows[playerID] > 0 {
+			total, err := ps.store.Matches().CountPlayerEvents(ctx, playerID, models.EventYellowCard)
+			if err != nil {
+				return fmt.Errorf("sarı kart sayısı alınamadı (oyuncu ID: %d): %v", playerID, err)
+			}
+			before := total - int64(yellows[playerID])
+			if before/yellowCardLimit < total/yellowCardLimit {
+				matches++
+			}
+		}
+		if matches > 0 {
+			absences = append(absences, newAbsence(playerID, teams[playerID], models.AbsenceSuspension, matches))
+		}
+	}
+
+	return ps.store.Matches().ReplaceAbsences(ctx, match.ID, absences)
+}
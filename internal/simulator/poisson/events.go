@@ -0,0 +1,200 @@
+package poisson
+
+import (
+	"math"
+	"sort"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+)
+
+// Maç akışı parametreleri
+const (
+	matchMinutes       = 90   // Normal süre
+	halfTimeMinute     = 45   // İlk yarının bittiği dakika
+	yellowCardLambda   = 1.8  // Takım başına maç ortalaması sarı kart
+	redCardProbability = 0.05 // Takım başına maçta kırmızı kart görme olasılığı
+	minSubstitutions   = 3    // Takım başına minimum oyuncu değişikliği
+	maxSubstitutions   = 5    // Takım başına maksimum oyuncu değişikliği
+	assistProbability  = 0.75 // Bir golün asistli olma olasılığı
+	injuryProbability  = 0.1  // Takım başına maçta sakatlık yaşanma olasılığı
+)
+
+// Mevkiye göre asist yapma ve kart görme ağırlıkları
+var (
+	assistWeights = map[string]float64{
+		models.PositionGoalkeeper: 0.1,
+		models.PositionDefender:   0.5,
+		models.PositionMidfielder: 1.5,
+		models.PositionForward:    1.0,
+	}
+	cardWeights = map[string]float64{
+		models.PositionGoalkeeper: 0.2,
+		models.PositionDefender:   1.5,
+		models.PositionMidfielder: 1.2,
+		models.PositionForward:    0.8,
+	}
+	injuryWeights = map[string]float64{
+		models.PositionGoalkeeper: 0.3,
+		models.PositionDefender:   1.0,
+		models.PositionMidfielder: 1.0,
+		models.PositionForward:    1.0,
+	}
+)
+
+// GenerateMatchEvents verilen skor için dakika dakika maç akışını üretir.
+// Gol sayıları Poisson lambda'larından geldiği için gol dakikaları [1, 90]
+// aralığına düzgün dağıtılır; bu, gol sayısı bilinen homojen bir Poisson
+// sürecinin koşullu dağılımıdır. Kartlar, sakatlıklar ve oyuncu değişiklikleri skordan
+// bağımsız olarak üretilir. Kadrolar verilmişse golcü, asist yapan, kart
+// gören ve sakatlanan oyuncular da atanır.
+func (ps *PoissonSimulator) GenerateMatchEvents(match models.Match, homeGoals, awayGoals int, homeSquad, awaySquad []models.Player) []models.MatchEvent {
+	homeTeamID := match.HomeTeamID
+	awayTeamID := match.AwayTeamID
+	squads := map[uint][]models.Player{
+		homeTeamID: homeSquad,
+		awayTeamID: awaySquad,
+	}
+
+	var events []models.MatchEvent
+	addTeamEvents := func(eventType string, teamID uint, minutes []uint) {
+		for _, minute := range minutes {
+			id := teamID
+			event := models.MatchEvent{
+				MatchID: match.ID,
+				Minute:  minute,
+				Type:    eventType,
+				TeamID:  &id,
+			}
+			ps.assignPlayers(&event, squads[teamID])
+			events = append(events, event)
+		}
+	}
+
+	// Goller
+	addTeamEvents(models.EventGoal, homeTeamID, ps.randomMinutes(homeGoals, 1, matchMinutes))
+	addTeamEvents(models.EventGoal, awayTeamID, ps.randomMinutes(awayGoals, 1, matchMinutes))
+
+	// Kartlar, sakatlıklar ve oyuncu değişiklikleri
+	for _, teamID := range []uint{homeTeamID, awayTeamID} {
+		addTeamEvents(models.EventYellowCard, teamID, ps.randomMinutes(ps.samplePoisson(yellowCardLambda), 1, matchMinutes))
+		if ps.rng.Float64() < redCardProbability {
+			addTeamEvents(models.EventRedCard, teamID, ps.randomMinutes(1, 20, matchMinutes))
+		}
+		if ps.rng.Float64() < injuryProbability {
+			addTeamEvents(models.EventInjury, teamID, ps.randomMinutes(1, 1, matchMinutes))
+		}
+		substitutions := minSubstitutions + ps.rng.Intn(maxSubstitutions-minSubstitutions+1)
+		addTeamEvents(models.EventSubstitution, teamID, ps.randomMinutes(substitutions, halfTimeMinute+1, matchMinutes-1))
+	}
+
+	// Devre arası ve maç sonu
+	events = append(events,
+		models.MatchEvent{MatchID: match.ID, Minute: halfTimeMinute, Type: models.EventHalfTime},
+		models.MatchEvent{MatchID: match.ID, Minute: matchMinutes, Type: models.EventFullTime},
+	)
+
+	// Dakikaya göre sırala; aynı dakikadaki devre arası/maç sonu en sona gelir
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].Minute != events[j].Minute {
+			return events[i].Minute < events[j].Minute
+		}
+		return events[i].TeamID != nil && events[j].TeamID == nil
+	})
+
+	// Her olaya o anki skoru yaz
+	var homeScore, awayScore uint
+	for i := range events {
+		if events[i].Type == models.EventGoal {
+			if *events[i].TeamID == homeTeamID {
+				homeScore++
+			} else {
+				awayScore++
+			}
+		}
+		events[i].HomeScore = homeScore
+		events[i].AwayScore = awayScore
+	}
+
+	return events
+}
+
+// assignPlayers olaya kadrodan ilgili oyuncuları atar
+func (ps *PoissonSimulator) assignPlayers(event *models.MatchEvent, squad []models.Player) {
+	switch event.Type {
+	case models.EventGoal:
+		event.PlayerID = ps.pickPlayer(squad, 0, func(p models.Player) float64 {
+			return p.ScoringShare
+		})
+		if event.PlayerID != nil && ps.rng.Float64() < assistProbability {
+			event.AssistPlayerID = ps.pickPlayer(squad, *event.PlayerID, func(p models.Player) float64 {
+				return assistWeights[p.Position] * float64(p.Rating) / 100.0
+			})
+		}
+	case models.EventYellowCard, models.EventRedCard:
+		event.PlayerID = ps.pickPlayer(squad, 0, func(p models.Player) float64 {
+			return cardWeights[p.Position]
+		})
+	case models.EventInjury:
+		event.PlayerID = ps.pickPlayer(squad, 0, func(p models.Player) float64 {
+			return injuryWeights[p.Position]
+		})
+	}
+}
+
+// pickPlayer kadrodan ağırlıklı rastgele bir oyuncu seçer; excludeID hariç tutulur.
+// Uygun oyuncu yoksa nil döner.
+func (ps *PoissonSimulator) pickPlayer(squad []models.Player, excludeID uint, weight func(models.Player) float64) *uint {
+	total := 0.0
+	for _, p := range squad {
+		if p.ID != excludeID && weight(p) > 0 {
+			total += weight(p)
+		}
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	r := ps.rng.Float64() * total
+	for _, p := range squad {
+		if p.ID == excludeID || weight(p) <= 0 {
+			continue
+		}
+		r -= weight(p)
+		if r <= 0 {
+			id := p.ID
+			return &id
+		}
+	}
+
+	// Kayan nokta yuvarlaması durumunda son uygun oyuncu
+	for i := len(squad) - 1; i >= 0; i-- {
+		if squad[i].ID != excludeID && weight(squad[i]) > 0 {
+			id := squad[i].ID
+			return &id
+		}
+	}
+	return nil
+}
+
+// randomMinutes [from, to] aralığında n adet rastgele dakika üretir
+func (ps *PoissonSimulator) randomMinutes(n int, from, to uint) []uint {
+	minutes := make([]uint, 0, n)
+	for i := 0; i < n; i++ {
+		minutes = append(minutes, from+uint(ps.rng.Intn(int(to-from+1))))
+	}
+	return minutes
+}
+
+// samplePoisson ek futbol faktörleri olmadan saf Poisson örneği üretir
+func (ps *PoissonSimulator) samplePoisson(lambda float64) int {
+	L := math.Exp(-lambda)
+	k := 0
+	p := 1.0
+
+	for p > L {
+		k++
+		p *= ps.rng.Float64()
+	}
+
+	return k - 1
+}
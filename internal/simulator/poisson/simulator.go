@@ -2,6 +2,8 @@
 package poisson
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math"
@@ -10,31 +12,47 @@ import (
 
 	"github.com/tarikbacak/insider-league-simulator/internal/db"
 	"github.com/tarikbacak/insider-league-simulator/internal/models"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/base"
 	simModels "github.com/tarikbacak/insider-league-simulator/internal/simulator/models"
-	"gorm.io/gorm"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/squad"
 )
 
 // PoissonSimulator Poisson dağılımı ile maç simülasyonu yapar
 type PoissonSimulator struct {
-	db  *gorm.DB
-	rng *rand.Rand // Global random number generator
+	store     db.Store
+	rng       *rand.Rand     // Global random number generator
+	observers base.Observers // Kaydedilen sonuçları bildirilen gözlemciler
 }
 
-// NewPoissonSimulator yeni bir Poisson simülatörü oluşturur
-func NewPoissonSimulator() *PoissonSimulator {
+// NewPoissonSimulator ligi verilen depodan okuyup yazan yeni bir Poisson simülatörü oluşturur
+func NewPoissonSimulator(store db.Store) *PoissonSimulator {
 	// Daha iyi random seed için çoklu kaynak kullan
 	seed := time.Now().UnixNano() + int64(rand.Intn(1000000))
+	return NewSeededPoissonSimulator(store, seed)
+}
+
+// NewSeededPoissonSimulator rastgele sayı üretecini verilen seed ile başlatan bir
+// Poisson simülatörü oluşturur; aynı seed aynı skor dizisini üretir
+func NewSeededPoissonSimulator(store db.Store, seed int64) *PoissonSimulator {
 	return &PoissonSimulator{
-		db:  db.GetDB(),
-		rng: rand.New(rand.NewSource(seed)),
+		store: store,
+		rng:   rand.New(rand.NewSource(seed)),
 	}
 }
 
-// GetTeamStats veritabanından takım istatistiklerini alır
-func (ps *PoissonSimulator) GetTeamStats(teamID uint) (*simModels.TeamStats, error) {
-	var dbStats models.TeamStats
-	err := ps.db.Select("team_id, avg_scored, avg_conceded, attack_strength, defense_strength").
-		Where("team_id = ?", teamID).First(&dbStats).Error
+// WithObservers kaydedilen maç sonuçlarını verilen gözlemcilere bildirir ve
+// simülatörün kendisini döndürür
+func (ps *PoissonSimulator) WithObservers(observers base.Observers) *PoissonSimulator {
+	ps.observers = observers
+	return ps
+}
+
+// GetTeamStats depodan takım istatistiklerini alır
+func (ps *PoissonSimulator) GetTeamStats(ctx context.Context, teamID uint) (*simModels.TeamStats, error) {
+	dbStats, err := ps.store.Teams().GetStats(ctx, teamID)
+	if errors.Is(err, db.ErrTeamNotFound) {
+		return nil, fmt.Errorf("%w: takım istatistikleri bulunamadı (ID: %d)", db.ErrTeamNotFound, teamID)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("takım istatistikleri alınamadı (ID: %d): %v", teamID, err)
 	}
@@ -50,29 +68,62 @@ func (ps *PoissonSimulator) GetTeamStats(teamID uint) (*simModels.TeamStats, err
 	return stats, nil
 }
 
+// GetSquad bir takımın kadrosunu depodan alır
+func (ps *PoissonSimulator) GetSquad(ctx context.Context, teamID uint) ([]models.Player, error) {
+	players, err := ps.store.Teams().ListPlayers(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("takım kadrosu alınamadı (ID: %d): %v", teamID, err)
+	}
+	return players, nil
+}
+
 // CalculateMatchLambdas bir maç için ev sahibi ve deplasman lambda değerlerini hesaplar
-func (ps *PoissonSimulator) CalculateMatchLambdas(homeTeamID, awayTeamID uint) (homeLambda, awayLambda float64, err error) {
-	homeStats, err := ps.GetTeamStats(homeTeamID)
+func (ps *PoissonSimulator) CalculateMatchLambdas(ctx context.Context, homeTeamID, awayTeamID uint) (homeLambda, awayLambda float64, err error) {
+	homeStats, err := ps.GetTeamStats(ctx, homeTeamID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	awayStats, err := ps.GetTeamStats(ctx, awayTeamID)
 	if err != nil {
 		return 0, 0, err
 	}
 
-	awayStats, err := ps.GetTeamStats(awayTeamID)
+	// Sakat ve cezalı oyuncular takımların efektif gücünü düşürür
+	homeFactors, err := ps.GetAvailabilityFactors(ctx, homeTeamID)
 	if err != nil {
 		return 0, 0, err
 	}
 
+	awayFactors, err := ps.GetAvailabilityFactors(ctx, awayTeamID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	homeLambda, awayLambda = ps.MatchLambdas(homeStats, awayStats, homeFactors, awayFactors)
+	return homeLambda, awayLambda, nil
+}
+
+// MatchLambdas takım istatistikleri ve kadro çarpanlarından bir maçın ev sahibi ve
+// deplasman lambda değerlerini hesaplar; depoya erişmez
+func (ps *PoissonSimulator) MatchLambdas(homeStats, awayStats *simModels.TeamStats, homeFactors, awayFactors squad.Factors) (homeLambda, awayLambda float64) {
+	homeAttack := homeStats.AttackStrength * homeFactors.Attack
+	homeDefense := homeStats.DefenseStrength / homeFactors.Defense
+	awayAttack := awayStats.AttackStrength * awayFactors.Attack
+	awayDefense := awayStats.DefenseStrength / awayFactors.Defense
+
 	// Temel lambda değerlerini hesapla
-	baseLambdaHome := homeStats.AttackStrength * awayStats.DefenseStrength * simModels.LeagueAverage
-	baseLambdaAway := awayStats.AttackStrength * homeStats.DefenseStrength * simModels.LeagueAverage
+	baseLambdaHome := homeAttack * awayDefense * simModels.LeagueAverage
+	baseLambdaAway := awayAttack * homeDefense * simModels.LeagueAverage
 
 	// Ev sahibi avantajı (gerçek futbolda %5-15 avantaj)
-	homeAdvantage := 1.0 + (ps.rng.Float64() * 0.15) // %0-15 arası random avantaj
+	homeAdvantage := 1.0 + (ps.rng.Float64() * simModels.HomeAdvantage) // %0-15 arası random avantaj
 	baseLambdaHome *= homeAdvantage
 
 	// Rastgele form faktörü (takımların o günkü performansı)
-	homeFormFactor := 0.8 + (ps.rng.Float64() * 0.4) // 0.8 - 1.2 arası
-	awayFormFactor := 0.8 + (ps.rng.Float64() * 0.4) // 0.8 - 1.2 arası
+	formRange := simModels.FormFactorMax - simModels.FormFactorMin
+	homeFormFactor := simModels.FormFactorMin + (ps.rng.Float64() * formRange) // 0.8 - 1.2 arası
+	awayFormFactor := simModels.FormFactorMin + (ps.rng.Float64() * formRange) // 0.8 - 1.2 arası
 
 	homeLambda = baseLambdaHome * homeFormFactor
 	awayLambda = baseLambdaAway * awayFormFactor
@@ -86,15 +137,14 @@ func (ps *PoissonSimulator) CalculateMatchLambdas(homeTeamID, awayTeamID uint) (
 	}
 
 	// Maximum değer kontrolü (çok yüksek lambda'ları engelle)
-	maxLambda := 4.0
-	if homeLambda > maxLambda {
-		homeLambda = maxLambda
+	if homeLambda > simModels.MaxLambda {
+		homeLambda = simModels.MaxLambda
 	}
-	if awayLambda > maxLambda {
-		awayLambda = maxLambda
+	if awayLambda > simModels.MaxLambda {
+		awayLambda = simModels.MaxLambda
 	}
 
-	return homeLambda, awayLambda, nil
+	return homeLambda, awayLambda
 }
 
 // GenerateGoals Poisson dağılımına göre gol sayısı üretir
@@ -138,92 +188,359 @@ func (ps *PoissonSimulator) GenerateGoals(lambda float64) int {
 	}
 
 	// Maximum skor limiti (çok absürd skorları engelle)
-	if baseGoals > 8 {
-		baseGoals = 8
+	if baseGoals > simModels.MaxGoals {
+		baseGoals = simModels.MaxGoals
 	}
 
 	return baseGoals
 }
 
 // SimulateMatch bir maçı simüle eder ve sonucu döndürür
-func (ps *PoissonSimulator) SimulateMatch(homeTeamID, awayTeamID uint) (homeGoals, awayGoals int, err error) {
-	homeLambda, awayLambda, err := ps.CalculateMatchLambdas(homeTeamID, awayTeamID)
+func (ps *PoissonSimulator) SimulateMatch(ctx context.Context, homeTeamID, awayTeamID uint) (homeGoals, awayGoals int, err error) {
+	result, err := ps.SimulateMatchDetailed(ctx, homeTeamID, awayTeamID)
 	if err != nil {
 		return 0, 0, err
 	}
 
-	homeGoals = ps.GenerateGoals(homeLambda)
-	awayGoals = ps.GenerateGoals(awayLambda)
+	return result.HomeGoals, result.AwayGoals, nil
+}
+
+// SimulateMatchDetailed bir maçı simüle eder ve skoru kullanılan lambda değerleriyle birlikte döndürür
+func (ps *PoissonSimulator) SimulateMatchDetailed(ctx context.Context, homeTeamID, awayTeamID uint) (*simModels.MatchResult, error) {
+	homeLambda, awayLambda, err := ps.CalculateMatchLambdas(ctx, homeTeamID, awayTeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	homeGoals := ps.GenerateGoals(homeLambda)
+	awayGoals := ps.GenerateGoals(awayLambda)
 
 	log.Printf("Maç simülasyonu: Takım %d (%d gol) vs Takım %d (%d gol) - Lambda: %.2f, %.2f",
 		homeTeamID, homeGoals, awayTeamID, awayGoals, homeLambda, awayLambda)
 
-	return homeGoals, awayGoals, nil
+	return &simModels.MatchResult{
+		HomeTeamID: homeTeamID,
+		AwayTeamID: awayTeamID,
+		HomeGoals:  homeGoals,
+		AwayGoals:  awayGoals,
+		HomeLambda: homeLambda,
+		AwayLambda: awayLambda,
+	}, nil
 }
 
-// PlayNextWeek bir sonraki haftanın maçlarını oynatır
-func (ps *PoissonSimulator) PlayNextWeek() error {
-	var nextWeek uint
-	result := ps.db.Model(&models.Match{}).
-		Where("home_goals IS NULL AND away_goals IS NULL").
-		Select("MIN(week)").
-		Scan(&nextWeek)
+// PlayMatch tek bir maçı ID'sine göre oynatır. Oynanmış bir maç yalnızca force
+// verildiğinde yeniden simüle edilir; bu durumda maçın olayları, doğurduğu
+// sakatlık/cezalar ve o haftadan itibaren yapılmış tahminler yenilenir.
+// Maçın durumu simülasyon kilidi alındıktan sonra okunur; böylece eşzamanlı
+// istekler aynı maçı iki kez oynatamaz.
+func (ps *PoissonSimulator) PlayMatch(ctx context.Context, matchID uint, force bool) (*simModels.MatchResult, error) {
+	var result *simModels.MatchResult
+	err := ps.store.WithSimulationLock(ctx, func(tx db.Store) error {
+		match, err := tx.Matches().FindMatch(ctx, matchID)
+		if errors.Is(err, db.ErrMatchNotFound) {
+			return err
+		}
+		if err != nil {
+			return fmt.Errorf("maç sorgulanamadı (ID: %d): %v", matchID, err)
+		}
 
-	if result.Error != nil {
-		return fmt.Errorf("sonraki hafta sorgulanamadı: %v", result.Error)
+		played := match.HomeGoals != nil && match.AwayGoals != nil
+		if played && !force {
+			return fmt.Errorf("%w (ID: %d)", base.ErrMatchAlreadyPlayed, matchID)
+		}
+
+		result, err = ps.withStore(tx).playMatch(ctx, &match)
+		if err != nil {
+			return err
+		}
+		result.Replayed = played
+
+		if played {
+			if err := tx.Predictions().DeletePredictionsFrom(ctx, match.Week); err != nil {
+				return fmt.Errorf("eski tahminler silinemedi (hafta: %d): %v", match.Week, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("oynanmamış maç bulunamadı")
+
+	ps.observers.NotifyResults(result.Week, []simModels.MatchResult{*result})
+	return result, nil
+}
+
+// playMatch bir maçı simüle eder; sonucu, olay akışını ve doğan sakatlık/cezaları kaydeder
+func (ps *PoissonSimulator) playMatch(ctx context.Context, match *models.Match) (*simModels.MatchResult, error) {
+	// Her maç ligin seed'inden türetilen kendi seed'iyle oynanır; böylece aynı
+	// seed'e sahip bir lig (ör. arşivden geri yüklenen) aynı sonuçları üretir
+	seed, err := db.NextMatchSeed(ctx, ps.store)
+	if err != nil {
+		return nil, err
+	}
+	ps.rng.Seed(seed)
+
+	// Kadrolar maç kaydedilmeden önce alınmalı; aksi halde bu maçta ceza
+	// sürecek oyuncular cezasını tamamlamış sayılır
+	homeSquad, err := ps.GetAvailableSquad(ctx, match.HomeTeamID)
+	if err != nil {
+		return nil, fmt.Errorf("ev sahibi kadrosu alınamadı: %v", err)
+	}
+	awaySquad, err := ps.GetAvailableSquad(ctx, match.AwayTeamID)
+	if err != nil {
+		return nil, fmt.Errorf("deplasman kadrosu alınamadı: %v", err)
+	}
+
+	result, err := ps.SimulateMatchDetailed(ctx, match.HomeTeamID, match.AwayTeamID)
+	if err != nil {
+		return nil, fmt.Errorf("maç simüle edilemedi: %v", err)
 	}
+	result.MatchID = match.ID
+	result.Week = match.Week
+
+	homeGoalsUint := uint(result.HomeGoals)
+	awayGoalsUint := uint(result.AwayGoals)
+	match.HomeGoals = &homeGoalsUint
+	match.AwayGoals = &awayGoalsUint
+	match.PlayedAt = time.Now()
+
+	if err := ps.store.Matches().SaveResult(ctx, match); err != nil {
+		return nil, fmt.Errorf("maç sonucu kaydedilemedi: %v", err)
+	}
+
+	events := ps.GenerateMatchEvents(*match, result.HomeGoals, result.AwayGoals, homeSquad, awaySquad)
+	if err := ps.store.Matches().ReplaceEvents(ctx, match.ID, events); err != nil {
+		return nil, fmt.Errorf("maç olayları kaydedilemedi: %v", err)
+	}
+
+	if err := ps.recordAbsences(ctx, *match, events); err != nil {
+		return nil, fmt.Errorf("sakatlık ve cezalar kaydedilemedi: %v", err)
+	}
+
+	return result, nil
+}
+
+// PlayNextWeek bir sonraki haftanın maçlarını oynatır ve sonuçlarını döndürür
+// Sıradaki hafta simülasyon kilidi altında belirlendiğinden eşzamanlı iki istek
+// aynı haftayı oynatamaz; ikinci istek bir sonraki haftayı oynatır.
+func (ps *PoissonSimulator) PlayNextWeek(ctx context.Context) ([]simModels.MatchResult, error) {
+	var (
+		week    uint
+		results []simModels.MatchResult
+	)
+	err := ps.store.WithSimulationLock(ctx, func(tx db.Store) error {
+		var err error
+		week, results, err = ps.withStore(tx).playNextWeek(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ps.observers.NotifyResults(week, results)
+	return results, nil
+}
+
+// PlayUntilWeek belirtilen hafta tamamlanana kadar haftaları tek bir transaction
+// içinde oynatır; herhangi bir hafta tamamlanamazsa hiçbir sonuç kaydedilmez.
+// Hafta zaten tamamlanmışsa boş sonuç döner. ctx iptal edilirse transaction
+// geri alınır.
+func (ps *PoissonSimulator) PlayUntilWeek(ctx context.Context, week uint) ([]simModels.MatchResult, error) {
+	var (
+		results     []simModels.MatchResult
+		playedWeeks []uint
+	)
+
+	err := ps.store.WithSimulationLock(ctx, func(tx db.Store) error {
+		txSim := ps.withStore(tx)
+		for {
+			if err := ctx.Err(); err != nil {
+				return fmt.Errorf("simülasyon durduruldu: %w", err)
+			}
+			remaining, err := tx.Matches().CountMatches(ctx, db.MatchFilter{UpToWeek: week, Played: db.Played(false)})
+			if err != nil {
+				return fmt.Errorf("oynanmamış maç sayısı sorgulanamadı: %v", err)
+			}
+			if remaining == 0 {
+				return nil
+			}
+
+			playedWeek, weekResults, err := txSim.playNextWeek(ctx)
+			if err != nil {
+				return fmt.Errorf("hafta oynatılırken hata: %w", err)
+			}
+
+			results = append(results, weekResults...)
+			playedWeeks = append(playedWeeks, playedWeek)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Tüm haftalar birlikte kaydedildiğinden gözlemciler commit sonrası bilgilendirilir
+	for _, playedWeek := range playedWeeks {
+		ps.observers.NotifyResults(playedWeek, resultsOfWeek(results, playedWeek))
+	}
+	return results, nil
+}
+
+// resultsOfWeek verilen haftaya ait maç sonuçlarını döndürür
+func resultsOfWeek(results []simModels.MatchResult, week uint) []simModels.MatchResult {
+	var weekResults []simModels.MatchResult
+	for _, result := range results {
+		if result.Week == week {
+			weekResults = append(weekResults, result)
+		}
+	}
+	return weekResults
+}
+
+// withStore simülatörün verilen depo (ör. transaction) üzerinde çalışan bir kopyasını döndürür
+func (ps *PoissonSimulator) withStore(store db.Store) *PoissonSimulator {
+	return &PoissonSimulator{
+		store: store,
+		rng:   ps.rng,
+	}
+}
+
+// playNextWeek bir sonraki haftanın maçlarını tek bir transaction içinde oynatır;
+// oynanan haftayı ve maç sonuçlarını döndürür. Herhangi bir maç simüle edilemez
+// veya kaydedilemezse hafta tamamen geri alınır ve başarısız maçları listeleyen
+// *base.WeekSimulationError döner.
+func (ps *PoissonSimulator) playNextWeek(ctx context.Context) (uint, []simModels.MatchResult, error) {
+	var (
+		week    uint
+		results []simModels.MatchResult
+	)
+
+	err := ps.store.Transaction(ctx, func(tx db.Store) error {
+		var err error
+		week, results, err = ps.withStore(tx).playWeek(ctx)
+		return err
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	log.Printf("%d. hafta maçları başarıyla simüle edildi", week)
+	return week, results, nil
+}
+
+// playWeek sıradaki haftanın maçlarını mevcut bağlantı üzerinde oynatır.
+// Her maç kendi savepoint'inde oynatılır; böylece bir maçın hatası diğer
+// maçların simülasyonunu engellemez ve tüm başarısız maçlar raporlanabilir.
+func (ps *PoissonSimulator) playWeek(ctx context.Context) (uint, []simModels.MatchResult, error) {
+	// Oynanmamış maçlar haftaya göre sıralı döner; ilk maçın haftası sıradaki haftadır
+	unplayed, err := ps.store.Matches().ListMatches(ctx, db.MatchFilter{Played: db.Played(false)})
+	if err != nil {
+		return 0, nil, fmt.Errorf("sonraki hafta sorgulanamadı: %v", err)
+	}
+	if len(unplayed) == 0 {
+		return 0, nil, base.ErrSeasonComplete
+	}
+	nextWeek := unplayed[0].Week
 
 	var matches []models.Match
-	if err := ps.db.Where("week = ? AND home_goals IS NULL AND away_goals IS NULL", nextWeek).Find(&matches).Error; err != nil {
-		return fmt.Errorf("hafta maçları sorgulanamadı: %v", err)
+	for _, match := range unplayed {
+		if match.Week == nextWeek {
+			matches = append(matches, match)
+		}
 	}
-	for _, match := range matches {
-		// Her maç için random seed'i yenile (daha fazla çeşitlilik)
-		ps.rng.Seed(time.Now().UnixNano() + int64(match.ID) + int64(ps.rng.Intn(10000)))
 
-		homeGoals, awayGoals, err := ps.SimulateMatch(match.HomeTeamID, match.AwayTeamID)
+	results := make([]simModels.MatchResult, 0, len(matches))
+	weekErr := &base.WeekSimulationError{Week: nextWeek, Errors: make(map[uint]error)}
+	for i := range matches {
+		var matchResult *simModels.MatchResult
+		err := ps.store.Transaction(ctx, func(tx db.Store) error {
+			var err error
+			matchResult, err = ps.withStore(tx).playMatch(ctx, &matches[i])
+			return err
+		})
 		if err != nil {
-			log.Printf("Maç simüle edilemedi (ID: %d): %v", match.ID, err)
+			log.Printf("Maç oynatılamadı (ID: %d): %v", matches[i].ID, err)
+			weekErr.FailedMatchIDs = append(weekErr.FailedMatchIDs, matches[i].ID)
+			weekErr.Errors[matches[i].ID] = err
 			continue
 		}
+		results = append(results, *matchResult)
+	}
 
-		homeGoalsUint := uint(homeGoals)
-		awayGoalsUint := uint(awayGoals)
-		match.HomeGoals = &homeGoalsUint
-		match.AwayGoals = &awayGoalsUint
-		match.PlayedAt = time.Now()
-
-		if err := ps.db.Save(&match).Error; err != nil {
-			log.Printf("Maç sonucu kaydedilemedi (ID: %d): %v", match.ID, err)
-		}
+	if len(weekErr.FailedMatchIDs) > 0 {
+		return 0, nil, weekErr
 	}
 
-	log.Printf("%d. hafta maçları başarıyla simüle edildi", nextWeek)
-	return nil
+	return nextWeek, results, nil
 }
 
-// PlayAllRemainingWeeks kalan tüm haftaları oynatır
-func (ps *PoissonSimulator) PlayAllRemainingWeeks() error {
-	for {
-		var count int64
-		if err := ps.db.Model(&models.Match{}).
-			Where("home_goals IS NULL AND away_goals IS NULL").
-			Count(&count).Error; err != nil {
-			return fmt.Errorf("oynanmamış maç sayısı sorgulanamadı: %v", err)
+// PlayAllRemainingWeeks kalan tüm haftaları oynatır. Her hafta ayrı bir
+// transaction'da ve simülasyon kilidi altında oynatılır; kalan maç kontrolü de
+// kilit içinde yapıldığından eşzamanlı isteklerle yarışmaz. Oynanan tüm maçların
+// sonuçlarını döndürür; oynanacak maç kalmamışsa base.ErrSeasonComplete döner.
+// Bir hafta başarısız olursa önceki haftaların sonuçları kalıcıdır ve hatayla
+// birlikte döner. ctx her hafta öncesinde kontrol edilir; hafta sırasında ya da
+// öncesinde iptal edilirse de o ana kadar oynanan haftaların sonuçları döner. progress nil değilse her haftadan sonra
+// oynanan ve başlangıçta kalan hafta sayısıyla çağrılır.
+func (ps *PoissonSimulator) PlayAllRemainingWeeks(ctx context.Context, progress base.ProgressFunc) ([]simModels.MatchResult, error) {
+	unplayed, err := ps.store.Matches().ListMatches(ctx, db.MatchFilter{Played: db.Played(false)})
+	if err != nil {
+		return nil, fmt.Errorf("kalan hafta sayısı sorgulanamadı: %v", err)
+	}
+	remainingWeeks := make(map[uint]bool)
+	for _, match := range unplayed {
+		remainingWeeks[match.Week] = true
+	}
+
+	var results []simModels.MatchResult
+	for played := 0; ; played++ {
+		if err := ctx.Err(); err != nil {
+			return results, fmt.Errorf("simülasyon %d hafta oynatıldıktan sonra durduruldu: %w", played, err)
 		}
 
-		if count == 0 {
-			log.Println("Tüm maçlar tamamlandı")
-			break
+		var (
+			done        bool
+			week        uint
+			weekResults []simModels.MatchResult
+		)
+		err := ps.store.WithSimulationLock(ctx, func(tx db.Store) error {
+			count, err := tx.Matches().CountMatches(ctx, db.MatchFilter{Played: db.Played(false)})
+			if err != nil {
+				return fmt.Errorf("oynanmamış maç sayısı sorgulanamadı: %v", err)
+			}
+
+			if count == 0 {
+				done = true
+				return nil
+			}
+
+			week, weekResults, err = ps.withStore(tx).playNextWeek(ctx)
+			if err != nil {
+				return fmt.Errorf("hafta oynatılırken hata: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			// Önceki haftalar kaydedilip bildirildi; sonuçları hatayla birlikte döner
+			return results, err
+		}
+		results = append(results, weekResults...)
+		ps.observers.NotifyResults(week, weekResults)
+		if progress != nil && !done {
+			total := len(remainingWeeks)
+			if played+1 > total {
+				total = played + 1
+			}
+			progress(played+1, total)
 		}
 
-		if err := ps.PlayNextWeek(); err != nil {
-			return fmt.Errorf("hafta oynatılırken hata: %v", err)
+		if done {
+			if played == 0 {
+				return nil, base.ErrSeasonComplete
+			}
+			log.Println("Tüm maçlar tamamlandı")
+			break
 		}
 	}
 
-	return nil
+	return results, nil
 }
@@ -0,0 +1,280 @@
+// Package season ligin tüm sezonunu başlangıç durumundan binlerce kez simüle
+// ederek Poisson maç modelinin ürettiği dağılımları raporlar
+package season
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/db"
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/base"
+	simModels "github.com/tarikbacak/insider-league-simulator/internal/simulator/models"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/poisson"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/squad"
+)
+
+// Sezon sayısı sınırları
+const (
+	DefaultSeasons = 10000  // Sezon sayısı verilmezse simüle edilen sezon sayısı
+	MaxSeasons     = 100000 // Tek bir analizde simüle edilebilecek en fazla sezon
+)
+
+// tiebreakSalt eşitlik bozma akışının seed'ini maç skorlarınınkinden ayırır
+const tiebreakSalt = 0x5eed
+
+// progressInterval ilerlemenin bildirildiği ve ctx'in kontrol edildiği sezon aralığı
+const progressInterval = 100
+
+// ErrNoFixtures ligde simüle edilecek maç olmadığında döner
+var ErrNoFixtures = errors.New("ligde simüle edilecek maç yok")
+
+// Report simüle edilen sezonların toplu sonuçları
+type Report struct {
+	Seasons          int          `json:"seasons" example:"10000"`           // Simulated seasons
+	Seed             int64        `json:"seed" example:"42"`                 // Random seed; the same seed reproduces the report
+	MatchesPerSeason int          `json:"matches_per_season" example:"12"`   // Matches played in each season
+	AverageGoals     float64      `json:"average_goals" example:"2.61"`      // Goals per match
+	AverageHome      float64      `json:"average_home_goals" example:"1.45"` // Home goals per match
+	AverageAway      float64      `json:"average_away_goals" example:"1.16"` // Away goals per match
+	HomeWinRate      float64      `json:"home_win_rate" example:"44.8"`      // Percentage of home wins
+	DrawRate         float64      `json:"draw_rate" example:"25.9"`          // Percentage of draws
+	AwayWinRate      float64      `json:"away_win_rate" example:"29.3"`      // Percentage of away wins
+	Teams            []TeamReport `json:"teams"`                             // Most likely champion first
+	DurationMS       int64        `json:"duration_ms" example:"184"`         // Time the simulation took
+}
+
+// TeamReport bir takımın simüle edilen sezonlardaki dağılımları
+type TeamReport struct {
+	TeamID              uint              `json:"team_id" example:"1"`
+	TeamName            string            `json:"team_name" example:"Galatasaray"`
+	TitleOdds           float64           `json:"title_odds" example:"61.2"`           // Percentage of seasons won
+	PositionOdds        []float64         `json:"position_odds"`                       // Percentage of seasons finished in each position, first place first
+	AveragePoints       float64           `json:"average_points" example:"11.4"`       // Points per season
+	MinPoints           int               `json:"min_points" example:"1"`              // Fewest points in a season
+	MaxPoints           int               `json:"max_points" example:"18"`             // Most points in a season
+	PointsDistribution  []PointsFrequency `json:"points_distribution"`                 // Seasons per points total, fewest points first
+	AverageGoalsFor     float64           `json:"average_goals_for" example:"8.1"`     // Goals scored per season
+	AverageGoalsAgainst float64           `json:"average_goals_against" example:"5.2"` // Goals conceded per season
+}
+
+// PointsFrequency bir puan toplamıyla biten sezonların oranı
+type PointsFrequency struct {
+	Points     int     `json:"points" example:"12"`
+	Seasons    int     `json:"seasons" example:"1480"`
+	Percentage float64 `json:"percentage" example:"14.8"`
+}
+
+// SeasonSimulator ligin fikstürünü Poisson modeliyle defalarca oynatır
+type SeasonSimulator struct {
+	store db.Store
+}
+
+// NewSeasonSimulator ligi verilen depodan okuyan bir sezon simülatörü oluşturur
+func NewSeasonSimulator(store db.Store) *SeasonSimulator {
+	return &SeasonSimulator{store: store}
+}
+
+// teamTally bir takımın tüm sezonlardaki sayaçları
+type teamTally struct {
+	titles       int
+	positions    []int
+	points       map[int]int // Puan -> o puanla biten sezon sayısı
+	pointsSum    int
+	goalsFor     int
+	goalsAgainst int
+}
+
+// seasonRow bir sezonun puan tablosundaki satır
+type seasonRow struct {
+	index        int // Takımın teams dizisindeki sırası
+	points       int
+	goalsFor     int
+	goalsAgainst int
+}
+
+// SimulateSeasons ligin tüm fikstürünü, oynanmış maçlar dahil, başlangıç
+// güçleri ve tam kadrolarla seasons kez oynatır. Sonuçlar kaydedilmez.
+// seed 0 ise rastgele bir seed seçilir ve raporda döner. ctx her sezon grubundan
+// önce kontrol edilir; progress nil değilse tamamlanan sezon sayısıyla çağrılır.
+func (ss *SeasonSimulator) SimulateSeasons(ctx context.Context, seasons int, seed int64, progress base.ProgressFunc) (*Report, error) {
+	if seasons < 1 || seasons > MaxSeasons {
+		return nil, fmt.Errorf("sezon sayısı 1 ile %d arasında olmalı", MaxSeasons)
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano() + int64(rand.Intn(1000000))
+	}
+	started := time.Now()
+
+	teams, err := ss.store.Teams().ListTeams(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("takımlar alınamadı: %v", err)
+	}
+	matches, err := ss.store.Matches().ListMatches(ctx, db.MatchFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("fikstür alınamadı: %v", err)
+	}
+	if len(teams) < 2 || len(matches) == 0 {
+		return nil, ErrNoFixtures
+	}
+
+	// Takım istatistikleri maçlar oynandıkça değişmediği için sezon başı gücünü verir
+	simulator := poisson.NewSeededPoissonSimulator(ss.store, seed)
+	index := make(map[uint]int, len(teams))
+	stats := make([]*simModels.TeamStats, len(teams))
+	for i, team := range teams {
+		index[team.ID] = i
+		if stats[i], err = simulator.GetTeamStats(ctx, team.ID); err != nil {
+			return nil, err
+		}
+	}
+	for _, match := range matches {
+		if _, ok := index[match.HomeTeamID]; !ok {
+			return nil, fmt.Errorf("%w: maç %d bilinmeyen bir takıma ait", db.ErrTeamNotFound, match.ID)
+		}
+		if _, ok := index[match.AwayTeamID]; !ok {
+			return nil, fmt.Errorf("%w: maç %d bilinmeyen bir takıma ait", db.ErrTeamNotFound, match.ID)
+		}
+	}
+
+	tallies := make([]teamTally, len(teams))
+	for i := range tallies {
+		tallies[i] = teamTally{positions: make([]int, len(teams)), points: make(map[int]int)}
+	}
+	var homeWins, draws, awayWins, homeGoals, awayGoals int
+	rows := make([]seasonRow, len(teams))
+	full := squad.FullStrength()
+	// Tam eşitlikleri bozmak için seed'den türeyen ayrı bir akış; maç skorlarının
+	// akışını değiştirmez, aynı seed aynı raporu verir
+	tiebreak := rand.New(rand.NewSource(seed ^ tiebreakSalt))
+
+	for played := 0; played < seasons; played++ {
+		if played%progressInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, fmt.Errorf("sezon simülasyonu durduruldu: %w", err)
+			}
+			if progress != nil && played > 0 {
+				progress(played, seasons)
+			}
+		}
+
+		for i := range rows {
+			rows[i] = seasonRow{index: i}
+		}
+		for _, match := range matches {
+			home, away := index[match.HomeTeamID], index[match.AwayTeamID]
+			homeLambda, awayLambda := simulator.MatchLambdas(stats[home], stats[away], full, full)
+			hg, ag := simulator.GenerateGoals(homeLambda), simulator.GenerateGoals(awayLambda)
+
+			homeGoals += hg
+			awayGoals += ag
+			rows[home].goalsFor += hg
+			rows[home].goalsAgainst += ag
+			rows[away].goalsFor += ag
+			rows[away].goalsAgainst += hg
+			switch {
+			case hg > ag:
+				homeWins++
+				rows[home].points += 3
+			case hg < ag:
+				awayWins++
+				rows[away].points += 3
+			default:
+				draws++
+				rows[home].points++
+				rows[away].points++
+			}
+		}
+
+		// Puan tablosu gibi sırala: puan, averaj, atılan gol; hepsi eşit olan
+		// takımların sırası karıştırılarak rastgele belirlenir
+		tiebreak.Shuffle(len(rows), func(i, j int) { rows[i], rows[j] = rows[j], rows[i] })
+		sort.SliceStable(rows, func(i, j int) bool {
+			if rows[i].points != rows[j].points {
+				return rows[i].points > rows[j].points
+			}
+			if gdI, gdJ := rows[i].goalsFor-rows[i].goalsAgainst, rows[j].goalsFor-rows[j].goalsAgainst; gdI != gdJ {
+				return gdI > gdJ
+			}
+			return rows[i].goalsFor > rows[j].goalsFor
+		})
+		for position, row := range rows {
+			tally := &tallies[row.index]
+			tally.positions[position]++
+			tally.points[row.points]++
+			tally.pointsSum += row.points
+			tally.goalsFor += row.goalsFor
+			tally.goalsAgainst += row.goalsAgainst
+		}
+		tallies[rows[0].index].titles++
+	}
+	if progress != nil {
+		progress(seasons, seasons)
+	}
+
+	totalMatches := float64(seasons * len(matches))
+	total := float64(seasons)
+	report := &Report{
+		Seasons:          seasons,
+		Seed:             seed,
+		MatchesPerSeason: len(matches),
+		AverageGoals:     float64(homeGoals+awayGoals) / totalMatches,
+		AverageHome:      float64(homeGoals) / totalMatches,
+		AverageAway:      float64(awayGoals) / totalMatches,
+		HomeWinRate:      float64(homeWins) / totalMatches * 100,
+		DrawRate:         float64(draws) / totalMatches * 100,
+		AwayWinRate:      float64(awayWins) / totalMatches * 100,
+		Teams:            make([]TeamReport, 0, len(teams)),
+	}
+	for i, team := range teams {
+		report.Teams = append(report.Teams, teamReport(team, tallies[i], total))
+	}
+	sort.SliceStable(report.Teams, func(i, j int) bool {
+		if report.Teams[i].TitleOdds != report.Teams[j].TitleOdds {
+			return report.Teams[i].TitleOdds > report.Teams[j].TitleOdds
+		}
+		return report.Teams[i].AveragePoints > report.Teams[j].AveragePoints
+	})
+	report.DurationMS = time.Since(started).Milliseconds()
+
+	return report, nil
+}
+
+// teamReport bir takımın sayaçlarını yüzdelere ve ortalamalara çevirir
+func teamReport(team models.Team, tally teamTally, total float64) TeamReport {
+	report := TeamReport{
+		TeamID:              team.ID,
+		TeamName:            team.Name,
+		TitleOdds:           float64(tally.titles) / total * 100,
+		PositionOdds:        make([]float64, len(tally.positions)),
+		AveragePoints:       float64(tally.pointsSum) / total,
+		AverageGoalsFor:     float64(tally.goalsFor) / total,
+		AverageGoalsAgainst: float64(tally.goalsAgainst) / total,
+		PointsDistribution:  make([]PointsFrequency, 0, len(tally.points)),
+	}
+	for position, count := range tally.positions {
+		report.PositionOdds[position] = float64(count) / total * 100
+	}
+
+	points := make([]int, 0, len(tally.points))
+	for p := range tally.points {
+		points = append(points, p)
+	}
+	sort.Ints(points)
+	for _, p := range points {
+		report.PointsDistribution = append(report.PointsDistribution, PointsFrequency{
+			Points:     p,
+			Seasons:    tally.points[p],
+			Percentage: float64(tally.points[p]) / total * 100,
+		})
+	}
+	if len(points) > 0 {
+		report.MinPoints = points[0]
+		report.MaxPoints = points[len(points)-1]
+	}
+	return report
+}
@@ -2,17 +2,33 @@
 package simulator
 
 import (
+	"github.com/tarikbacak/insider-league-simulator/internal/db"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/backtest"
 	"github.com/tarikbacak/insider-league-simulator/internal/simulator/base"
 	"github.com/tarikbacak/insider-league-simulator/internal/simulator/montecarlo"
 	"github.com/tarikbacak/insider-league-simulator/internal/simulator/poisson"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/season"
 )
 
-// GetPoissonSimulator returns a new Poisson-based match simulator
-func GetPoissonSimulator() base.Simulator {
-	return poisson.NewPoissonSimulator()
+// GetPoissonSimulator returns a new Poisson-based match simulator playing the league in store
+// Committed results are reported to observers.
+func GetPoissonSimulator(store db.Store, observers base.Observers) base.Simulator {
+	return poisson.NewPoissonSimulator(store).WithObservers(observers)
 }
 
-// GetMonteCarloPredictor returns a new Monte Carlo championship predictor
-func GetMonteCarloPredictor(iterations int) base.Predictor {
-	return montecarlo.NewMonteCarloPredictor(iterations)
+// GetMonteCarloPredictor returns a new Monte Carlo championship predictor for the league in store
+// Saved predictions are reported to observers.
+func GetMonteCarloPredictor(store db.Store, iterations int, observers base.Observers) base.Predictor {
+	return montecarlo.NewMonteCarloPredictor(store, iterations).WithObservers(observers)
+}
+
+// GetSeasonSimulator returns a new simulator that plays the full season of the league in store many times
+func GetSeasonSimulator(store db.Store) *season.SeasonSimulator {
+	return season.NewSeasonSimulator(store)
+}
+
+// GetBacktester returns a new backtester that replays played seasons week by week
+// and scores the forecasts of the match and championship models
+func GetBacktester(samples, iterations int, seed int64) *backtest.Backtester {
+	return backtest.NewBacktester(samples, iterations, seed)
 }
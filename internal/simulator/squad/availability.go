@@ -0,0 +1,108 @@
+// Package squad kadro durumunun (sakatlık, ceza) takım gücüne etkisini hesaplar
+package squad
+
+import (
+	"sort"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+)
+
+// MinFactor eksik oyuncular nedeniyle bir takımın gücünün düşebileceği alt sınır
+const MinFactor = 0.5
+
+// Güç hesabında kullanılan ideal ilk 11'in mevkilere göre dağılımı
+var (
+	attackSlots = map[string]int{
+		models.PositionForward:    2,
+		models.PositionMidfielder: 4,
+	}
+	defenseSlots = map[string]int{
+		models.PositionGoalkeeper: 1,
+		models.PositionDefender:   4,
+	}
+)
+
+// Factors takımın hücum ve savunma gücüne uygulanacak çarpanlar
+// 1.0 tam kadroyu, daha düşük değerler eksik oyuncuları ifade eder
+type Factors struct {
+	Attack  float64 `json:"attack"`
+	Defense float64 `json:"defense"`
+}
+
+// FullStrength eksiksiz bir kadronun çarpanlarını döndürür
+func FullStrength() Factors {
+	return Factors{Attack: 1.0, Defense: 1.0}
+}
+
+// StrengthFactors forma giyemeyecek oyuncular çıkarıldığında kurulabilecek en iyi
+// ilk 11'in reytingini tam kadronunkiyle karşılaştırarak çarpanları hesaplar.
+// Kadrosu olmayan takımlar için tam güç döner.
+func StrengthFactors(players []models.Player, unavailable map[uint]bool) Factors {
+	factors := FullStrength()
+
+	if full := lineupRating(players, nil, attackSlots); full > 0 {
+		factors.Attack = clampFactor(lineupRating(players, unavailable, attackSlots) / full)
+	}
+	if full := lineupRating(players, nil, defenseSlots); full > 0 {
+		factors.Defense = clampFactor(lineupRating(players, unavailable, defenseSlots) / full)
+	}
+
+	return factors
+}
+
+// Unavailable en az matchesAhead+1 maç daha forma giyemeyecek oyuncuları döndürür;
+// matchesAhead = 0 takımın bir sonraki maçını ifade eder
+func Unavailable(remaining map[uint]uint, matchesAhead uint) map[uint]bool {
+	unavailable := make(map[uint]bool)
+	for playerID, matches := range remaining {
+		if matches > matchesAhead {
+			unavailable[playerID] = true
+		}
+	}
+	return unavailable
+}
+
+// Available forma giyebilecek oyuncuları döndürür
+func Available(players []models.Player, unavailable map[uint]bool) []models.Player {
+	available := make([]models.Player, 0, len(players))
+	for _, p := range players {
+		if !unavailable[p.ID] {
+			available = append(available, p)
+		}
+	}
+	return available
+}
+
+// lineupRating her mevkideki en yüksek reytingli uygun oyuncuların reyting toplamını hesaplar
+// Doldurulamayan mevkiler 0 reytingle sayılır
+func lineupRating(players []models.Player, unavailable map[uint]bool, slots map[string]int) float64 {
+	ratings := make(map[string][]int)
+	for _, p := range players {
+		if unavailable[p.ID] {
+			continue
+		}
+		ratings[p.Position] = append(ratings[p.Position], p.Rating)
+	}
+
+	total := 0
+	for position, count := range slots {
+		positionRatings := ratings[position]
+		sort.Sort(sort.Reverse(sort.IntSlice(positionRatings)))
+		for i := 0; i < count && i < len(positionRatings); i++ {
+			total += positionRatings[i]
+		}
+	}
+
+	return float64(total)
+}
+
+// clampFactor çarpanı [MinFactor, 1] aralığında tutar
+func clampFactor(f float64) float64 {
+	if f < MinFactor {
+		return MinFactor
+	}
+	if f > 1.0 {
+		return 1.0
+	}
+	return f
+}
@@ -0,0 +1,76 @@
+// Package stream distributes real-time league events to connected clients
+package stream
+
+import "sync"
+
+// Event types published to subscribers
+const (
+	EventConnected     = "connected"      // Sent to a new subscriber with the current standings
+	EventMatchResult   = "match_result"   // A match result was saved
+	EventWeekCompleted = "week_completed" // All results of a week were saved
+	EventStandings     = "standings"      // The league table changed
+	EventPredictions   = "predictions"    // New championship predictions were calculated
+	EventLeagueReset   = "league_reset"   // Results were reset (new fixture, rewind or restore)
+)
+
+// subscriberBuffer is the number of events buffered per subscriber
+// Events for a subscriber whose buffer is full are dropped.
+const subscriberBuffer = 64
+
+// Event is a single message sent to subscribers
+type Event struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+// Hub fans out published events to all current subscribers
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[chan Event]struct{}
+}
+
+// NewHub creates an empty hub
+func NewHub() *Hub {
+	return &Hub{subscribers: make(map[chan Event]struct{})}
+}
+
+// Subscribe registers a new subscriber
+// The returned function must be called to unsubscribe; it closes the channel.
+func (h *Hub) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers, ch)
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish sends an event to every subscriber without blocking
+func (h *Hub) Publish(eventType string, data interface{}) {
+	event := Event{Type: eventType, Data: data}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for ch := range h.subscribers {
+		select {
+		case ch <- event:
+		default: // Slow subscriber, drop the event
+		}
+	}
+}
+
+// SubscriberCount returns the number of connected subscribers
+func (h *Hub) SubscriberCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subscribers)
+}
@@ -0,0 +1,210 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+	"gorm.io/gorm"
+)
+
+// Delivery settings
+const (
+	// DefaultMaxAttempts is how many times a delivery is tried before it is marked failed
+	DefaultMaxAttempts = 6
+	// DefaultPollInterval is how often the queue is checked for due deliveries
+	DefaultPollInterval = 5 * time.Second
+
+	// baseRetryDelay is the wait after the first failed attempt; it doubles with every attempt
+	baseRetryDelay = 10 * time.Second
+	// maxRetryDelay caps the wait between attempts
+	maxRetryDelay = time.Hour
+	// deliveryTimeout limits a single HTTP request to a webhook
+	deliveryTimeout = 10 * time.Second
+	// deliveryBatchSize is how many due deliveries are loaded at a time
+	deliveryBatchSize = 20
+	// maxErrorBodyLength is how much of an error response is kept in LastError
+	maxErrorBodyLength = 512
+)
+
+// Dispatcher queues events for webhooks, sends queued deliveries and reschedules failed ones
+type Dispatcher struct {
+	Client       *http.Client
+	MaxAttempts  int
+	PollInterval time.Duration
+
+	db   *gorm.DB
+	wake chan struct{}
+}
+
+// NewDispatcher creates a dispatcher with the default settings that stores its queue in database
+func NewDispatcher(database *gorm.DB) *Dispatcher {
+	return &Dispatcher{
+		db:           database,
+		Client:       &http.Client{Timeout: deliveryTimeout},
+		MaxAttempts:  DefaultMaxAttempts,
+		PollInterval: DefaultPollInterval,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// Wake makes a running dispatcher check the queue without waiting for the next poll
+func (d *Dispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run sends due deliveries until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Webhook delivery error: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// DeliverDue sends every pending delivery whose next attempt is due
+// It returns the number of attempts made.
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	database := d.db.WithContext(ctx)
+	attempted := 0
+
+	for ctx.Err() == nil {
+		var due []models.WebhookDelivery
+		err := database.Where("status = ? AND next_attempt_at <= ?", models.DeliveryPending, time.Now()).
+			Order("id").Limit(deliveryBatchSize).Find(&due).Error
+		if err != nil {
+			return attempted, err
+		}
+		if len(due) == 0 {
+			return attempted, nil
+		}
+
+		for _, delivery := range due {
+			claimed, err := claim(database, &delivery)
+			if err != nil {
+				return attempted, err
+			}
+			if !claimed {
+				continue // Another dispatcher is sending it
+			}
+			if err := d.attempt(ctx, database, delivery); err != nil {
+				return attempted, err
+			}
+			attempted++
+		}
+	}
+	return attempted, ctx.Err()
+}
+
+// claim reserves a delivery for one attempt by counting the attempt up front
+// The next attempt is pushed back so that the delivery is not picked up again
+// while it is being sent, and is retried later if the process stops midway.
+func claim(database *gorm.DB, delivery *models.WebhookDelivery) (bool, error) {
+	result := database.Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND attempts = ?", delivery.ID, models.DeliveryPending, delivery.Attempts).
+		Updates(map[string]interface{}{
+			"attempts":        delivery.Attempts + 1,
+			"next_attempt_at": time.Now().Add(retryDelay(delivery.Attempts + 1)),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	delivery.Attempts++
+	return result.RowsAffected == 1, nil
+}
+
+// attempt sends a claimed delivery and records the outcome
+func (d *Dispatcher) attempt(ctx context.Context, database *gorm.DB, delivery models.WebhookDelivery) error {
+	var hook models.Webhook
+	err := database.First(&hook, delivery.WebhookID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !hook.Active) {
+		return database.Model(&delivery).Updates(map[string]interface{}{
+			"status":     models.DeliveryFailed,
+			"last_error": "webhook was deleted or deactivated",
+		}).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	status, sendErr := d.send(ctx, hook, delivery)
+	updates := map[string]interface{}{
+		"response_status": status,
+	}
+	if sendErr == nil {
+		now := time.Now()
+		updates["status"] = models.DeliveryDelivered
+		updates["delivered_at"] = &now
+		updates["last_error"] = ""
+	} else {
+		updates["last_error"] = sendErr.Error()
+		if delivery.Attempts >= d.MaxAttempts {
+			updates["status"] = models.DeliveryFailed
+			log.Printf("Webhook delivery %d to %s failed after %d attempts: %v", delivery.ID, hook.URL, delivery.Attempts, sendErr)
+		}
+	}
+	return database.Model(&delivery).Updates(updates).Error
+}
+
+// send POSTs the delivery to the webhook and returns the response status
+// Any status other than 2xx is an error.
+func (d *Dispatcher) send(ctx context.Context, hook models.Webhook, delivery models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "insider-league-simulator-webhook")
+	request.Header.Set(EventHeader, delivery.Event)
+	request.Header.Set(DeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	request.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(SignatureHeader, Sign(hook.Secret, timestamp, body))
+
+	response, err := d.Client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorBodyLength))
+		return response.StatusCode, fmt.Errorf("unexpected response status %d: %s", response.StatusCode, bytes.TrimSpace(snippet))
+	}
+	io.Copy(io.Discard, response.Body)
+	return response.StatusCode, nil
+}
+
+// retryDelay returns how long to wait after the given number of failed attempts
+func retryDelay(attempts int) time.Duration {
+	delay := baseRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
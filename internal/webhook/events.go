@@ -0,0 +1,246 @@
+package webhook
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/db"
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+	"github.com/tarikbacak/insider-league-simulator/internal/simulator/base"
+	simModels "github.com/tarikbacak/insider-league-simulator/internal/simulator/models"
+	"gorm.io/gorm"
+)
+
+// MatchData is a played match in an event payload
+type MatchData struct {
+	MatchID    uint   `json:"match_id"`
+	HomeTeamID uint   `json:"home_team_id"`
+	HomeTeam   string `json:"home_team"`
+	AwayTeamID uint   `json:"away_team_id"`
+	AwayTeam   string `json:"away_team"`
+	HomeGoals  uint   `json:"home_goals"`
+	AwayGoals  uint   `json:"away_goals"`
+}
+
+// WeekCompletedData is the payload of the week.completed event
+type WeekCompletedData struct {
+	Week      uint              `json:"week"`
+	Results   []MatchData       `json:"results"`
+	Standings []models.Standing `json:"standings"`
+}
+
+// SeasonEndedData is the payload of the season.ended event
+type SeasonEndedData struct {
+	Champion  models.Standing   `json:"champion"`
+	Standings []models.Standing `json:"standings"`
+}
+
+// PredictedChampion is a team's championship probability in an event payload
+type PredictedChampion struct {
+	TeamID      uint    `json:"team_id"`
+	TeamName    string  `json:"team_name"`
+	Probability float64 `json:"probability"`
+}
+
+// ChampionChangedData is the payload of the champion.changed event
+type ChampionChangedData struct {
+	Week             uint              `json:"week"`
+	PreviousWeek     uint              `json:"previous_week"`
+	Champion         PredictedChampion `json:"champion"`
+	PreviousChampion PredictedChampion `json:"previous_champion"`
+}
+
+// Observers returns the simulation observers that queue webhook events for
+// the league in store
+// Give them to the simulators and predictors that play that league.
+func (d *Dispatcher) Observers(store db.Store) base.Observers {
+	return base.Observers{
+		Results: []base.ResultObserver{func(week uint, results []simModels.MatchResult) {
+			d.queueResultEvents(context.Background(), store, week, results)
+		}},
+		Predictions: []base.PredictionObserver{func(week uint, probabilities map[uint]float64) {
+			d.queuePredictionEvents(context.Background(), store, week, probabilities)
+		}},
+	}
+}
+
+// Start runs the dispatcher in the background until ctx is cancelled
+func (d *Dispatcher) Start(ctx context.Context) {
+	go d.Run(ctx)
+}
+
+// hasActiveWebhooks reports whether any webhook would receive an event
+// Used to skip building payloads nobody receives.
+func hasActiveWebhooks(database *gorm.DB) bool {
+	var count int64
+	if err := database.Model(&models.Webhook{}).Where("active = ?", true).Count(&count).Error; err != nil {
+		log.Printf("Could not check webhooks: %v", err)
+		return false
+	}
+	return count > 0
+}
+
+// queueResultEvents queues week.completed when results play the last unplayed
+// match of a week, and season.ended when they play the last match of the season.
+// Re-simulating a match of a week that was already complete (force) changes
+// its result but completes nothing, so it queues neither event.
+func (d *Dispatcher) queueResultEvents(ctx context.Context, store db.Store, week uint, results []simModels.MatchResult) {
+	if !simModels.HasFirstPlays(results) || !hasActiveWebhooks(d.db) {
+		return
+	}
+
+	matches, err := store.Matches().ListMatches(ctx, db.MatchFilter{Week: week})
+	if err != nil {
+		log.Printf("Could not queue webhook events of week %d: %v", week, err)
+		return
+	}
+	teams, err := store.Teams().ListTeams(ctx)
+	if err != nil {
+		log.Printf("Could not queue webhook events of week %d: %v", week, err)
+		return
+	}
+	names := make(map[uint]string, len(teams))
+	for _, team := range teams {
+		names[team.ID] = team.Name
+	}
+
+	played := make([]MatchData, 0, len(matches))
+	for _, match := range matches {
+		if match.HomeGoals == nil || match.AwayGoals == nil {
+			return // The week is not complete yet
+		}
+		played = append(played, MatchData{
+			MatchID:    match.ID,
+			HomeTeamID: match.HomeTeamID,
+			HomeTeam:   names[match.HomeTeamID],
+			AwayTeamID: match.AwayTeamID,
+			AwayTeam:   names[match.AwayTeamID],
+			HomeGoals:  *match.HomeGoals,
+			AwayGoals:  *match.AwayGoals,
+		})
+	}
+
+	// Several weeks played in one transaction are reported one by one, so the
+	// table is the one after this week rather than the current one
+	standings, err := db.CalculateStandingsAfterWeek(ctx, store, week)
+	if err != nil {
+		log.Printf("Could not queue webhook events of week %d: %v", week, err)
+		return
+	}
+
+	if err := d.Enqueue(EventWeekCompleted, WeekCompletedData{
+		Week:      week,
+		Results:   played,
+		Standings: standings,
+	}); err != nil {
+		log.Printf("Could not queue %s event: %v", EventWeekCompleted, err)
+	}
+
+	// The season ends with the week whose results played its last match. When
+	// several weeks are played in one transaction every one of them sees no
+	// unplayed matches afterwards, so a week is passed over while a later week
+	// was played after it; normally that leaves only the last week.
+	unplayed, err := store.Matches().CountMatches(ctx, db.MatchFilter{Played: db.Played(false)})
+	if err != nil {
+		log.Printf("Could not check for the end of the season: %v", err)
+		return
+	}
+	if unplayed > 0 || len(standings) == 0 {
+		return
+	}
+	var playedAt time.Time
+	for _, match := range matches {
+		if match.PlayedAt.After(playedAt) {
+			playedAt = match.PlayedAt
+		}
+	}
+	all, err := store.Matches().ListMatches(ctx, db.MatchFilter{})
+	if err != nil {
+		log.Printf("Could not check for the end of the season: %v", err)
+		return
+	}
+	for _, match := range all {
+		if match.Week > week && match.PlayedAt.After(playedAt) {
+			return
+		}
+	}
+	if err := d.Enqueue(EventSeasonEnded, SeasonEndedData{
+		Champion:  standings[0],
+		Standings: standings,
+	}); err != nil {
+		log.Printf("Could not queue %s event: %v", EventSeasonEnded, err)
+	}
+}
+
+// queuePredictionEvents queues champion.changed when the favourite of the new
+// predictions differs from the favourite of the latest earlier week's predictions
+func (d *Dispatcher) queuePredictionEvents(ctx context.Context, store db.Store, week uint, probabilities map[uint]float64) {
+	if !hasActiveWebhooks(d.db) {
+		return
+	}
+
+	var previous []models.Prediction
+	for earlier := int(week) - 1; earlier >= 0 && len(previous) == 0; earlier-- {
+		var err error
+		if previous, err = store.Predictions().ListPredictions(ctx, uint(earlier)); err != nil {
+			log.Printf("Could not load previous predictions: %v", err)
+			return
+		}
+	}
+	if len(previous) == 0 {
+		return // Nothing to compare with
+	}
+
+	previousProbabilities := make(map[uint]float64, len(previous))
+	for _, prediction := range previous {
+		previousProbabilities[prediction.TeamID] = prediction.Probability
+	}
+
+	championID := favourite(probabilities)
+	previousID := favourite(previousProbabilities)
+	if championID == previousID {
+		return
+	}
+
+	names := make(map[uint]string, 2)
+	for _, teamID := range []uint{championID, previousID} {
+		team, err := store.Teams().FindTeam(ctx, teamID)
+		if err != nil {
+			log.Printf("Could not load predicted champions: %v", err)
+			return
+		}
+		names[team.ID] = team.Name
+	}
+
+	if err := d.Enqueue(EventChampionChanged, ChampionChangedData{
+		Week:         week,
+		PreviousWeek: previous[0].Week,
+		Champion: PredictedChampion{
+			TeamID:      championID,
+			TeamName:    names[championID],
+			Probability: probabilities[championID],
+		},
+		PreviousChampion: PredictedChampion{
+			TeamID:      previousID,
+			TeamName:    names[previousID],
+			Probability: previousProbabilities[previousID],
+		},
+	}); err != nil {
+		log.Printf("Could not queue %s event: %v", EventChampionChanged, err)
+	}
+}
+
+// favourite returns the team with the highest probability
+// Ties go to the lower team ID so that equal predictions have the same favourite.
+func favourite(probabilities map[uint]float64) uint {
+	var best uint
+	bestProbability := -1.0
+	for teamID, probability := range probabilities {
+		if probability > bestProbability || (probability == bestProbability && teamID < best) {
+			best = teamID
+			bestProbability = probability
+		}
+	}
+	return best
+}
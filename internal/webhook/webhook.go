@@ -0,0 +1,207 @@
+// Package webhook delivers league events to registered HTTP endpoints
+// Events are written to a persistent queue first and sent by a background
+// dispatcher, so deliveries survive restarts and failed ones are retried.
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tarikbacak/insider-league-simulator/internal/db"
+	"github.com/tarikbacak/insider-league-simulator/internal/models"
+	"gorm.io/gorm"
+)
+
+// Event types sent to webhooks
+const (
+	EventWeekCompleted   = "week.completed"   // Every match of a week has been played
+	EventSeasonEnded     = "season.ended"     // The last match of the season has been played
+	EventChampionChanged = "champion.changed" // The predicted champion differs from the previous week's
+	EventPing            = "ping"             // Test event sent on request
+)
+
+// Events lists the event types a webhook can subscribe to
+var Events = []string{EventWeekCompleted, EventSeasonEnded, EventChampionChanged, EventPing}
+
+// Request headers of a delivery
+const (
+	EventHeader     = "X-League-Event"     // Event type
+	DeliveryHeader  = "X-League-Delivery"  // Delivery ID, the same for every retry
+	TimestampHeader = "X-League-Timestamp" // Unix time of the attempt, part of the signed content
+	SignatureHeader = "X-League-Signature" // "sha256=" followed by the hex HMAC of "<timestamp>.<body>"
+)
+
+// Envelope is the JSON body sent to webhooks
+type Envelope struct {
+	Event      string      `json:"event"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// IsValidEvent reports whether event is a known event type
+func IsValidEvent(event string) bool {
+	for _, known := range Events {
+		if event == known {
+			return true
+		}
+	}
+	return false
+}
+
+// SubscribedEvents returns the events a webhook receives; nil means every event
+func SubscribedEvents(hook models.Webhook) []string {
+	if hook.Events == "" {
+		return nil
+	}
+	return strings.Split(hook.Events, ",")
+}
+
+// JoinEvents encodes a list of events for storage in models.Webhook.Events
+func JoinEvents(events []string) string {
+	return strings.Join(events, ",")
+}
+
+// Subscribes reports whether the webhook receives the given event
+func Subscribes(hook models.Webhook, event string) bool {
+	events := SubscribedEvents(hook)
+	if events == nil {
+		return true
+	}
+	for _, subscribed := range events {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateSecret returns a random signing secret
+func GenerateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Sign returns the value of the signature header for a delivery body
+// Receivers verify a delivery by computing the same HMAC-SHA256 over the
+// timestamp header, a dot and the raw body with their copy of the secret.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Register stores a new webhook, filling in its ID
+func (d *Dispatcher) Register(ctx context.Context, hook *models.Webhook) error {
+	return d.db.WithContext(ctx).Create(hook).Error
+}
+
+// List returns every registered webhook ordered by ID
+func (d *Dispatcher) List(ctx context.Context) ([]models.Webhook, error) {
+	var hooks []models.Webhook
+	err := d.db.WithContext(ctx).Order("id").Find(&hooks).Error
+	return hooks, err
+}
+
+// Find loads a webhook by ID, returning db.ErrWebhookNotFound if it does not exist
+func (d *Dispatcher) Find(ctx context.Context, id uint) (models.Webhook, error) {
+	return db.FindWebhook(d.db.WithContext(ctx), id)
+}
+
+// Remove deletes a webhook together with its delivery history
+func (d *Dispatcher) Remove(ctx context.Context, hook models.Webhook) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", hook.ID).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&hook).Error
+	})
+}
+
+// Deliveries returns the newest deliveries of a webhook first, optionally only
+// those with the given status
+func (d *Dispatcher) Deliveries(ctx context.Context, hookID uint, status string, limit int) ([]models.WebhookDelivery, error) {
+	query := d.db.WithContext(ctx).Where("webhook_id = ?", hookID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	deliveries := []models.WebhookDelivery{}
+	err := query.Order("id DESC").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+// Enqueue queues an event for every active webhook subscribed to it
+// The deliveries are stored in the database and the dispatcher is woken up to send them.
+func (d *Dispatcher) Enqueue(event string, data interface{}) error {
+	var hooks []models.Webhook
+	if err := d.db.Where("active = ?", true).Find(&hooks).Error; err != nil {
+		return err
+	}
+
+	var payload string
+	queued := 0
+	for _, hook := range hooks {
+		if !Subscribes(hook, event) {
+			continue
+		}
+		if payload == "" {
+			var err error
+			if payload, err = encodePayload(event, data); err != nil {
+				return err
+			}
+		}
+		if _, err := queue(d.db, hook, event, payload); err != nil {
+			return err
+		}
+		queued++
+	}
+
+	if queued > 0 {
+		d.Wake()
+	}
+	return nil
+}
+
+// Ping queues a ping event for a single webhook, whatever events it subscribes to
+func (d *Dispatcher) Ping(hook models.Webhook) (models.WebhookDelivery, error) {
+	payload, err := encodePayload(EventPing, map[string]uint{"webhook_id": hook.ID})
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+
+	delivery, err := queue(d.db, hook, EventPing, payload)
+	if err == nil {
+		d.Wake()
+	}
+	return delivery, err
+}
+
+// encodePayload builds the JSON body of an event
+func encodePayload(event string, data interface{}) (string, error) {
+	payload, err := json.Marshal(Envelope{Event: event, OccurredAt: time.Now(), Data: data})
+	return string(payload), err
+}
+
+// queue stores a pending delivery of the payload to the webhook
+func queue(database *gorm.DB, hook models.Webhook, event, payload string) (models.WebhookDelivery, error) {
+	delivery := models.WebhookDelivery{
+		WebhookID:     hook.ID,
+		Event:         event,
+		Payload:       payload,
+		Status:        models.DeliveryPending,
+		NextAttemptAt: time.Now(),
+	}
+	err := database.Create(&delivery).Error
+	return delivery, err
+}
@@ -0,0 +1,11 @@
+// Package migrations embeds the versioned SQL migrations of the database schema
+// Each supported driver has its own directory of NNN_name.up.sql and
+// NNN_name.down.sql files, applied in version order by the db package.
+package migrations
+
+import "embed"
+
+// Files holds the postgres and sqlite migration directories
+//
+//go:embed postgres/*.sql sqlite/*.sql
+var Files embed.FS
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS predictions;
+DROP TABLE IF EXISTS team_stats;
+DROP TABLE IF EXISTS matches;
+DROP TABLE IF EXISTS teams;
@@ -0,0 +1,71 @@
+-- Teams table
+-- Stores each team with its strength ratings
+CREATE TABLE IF NOT EXISTS teams (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    name TEXT NOT NULL,
+    attack BIGINT,  -- Offensive strength of the team
+    defense BIGINT, -- Defensive strength of the team
+    CONSTRAINT uni_teams_name UNIQUE (name)
+);
+CREATE INDEX IF NOT EXISTS idx_teams_deleted_at ON teams(deleted_at);
+
+-- Matches table
+-- Stores match details including week, participating teams, and results
+-- If home_goals and away_goals are NULL, the match has not been played yet
+CREATE TABLE IF NOT EXISTS matches (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    week BIGINT NOT NULL,
+    home_team_id BIGINT NOT NULL,
+    away_team_id BIGINT NOT NULL,
+    home_goals BIGINT,     -- NULL if not yet played
+    away_goals BIGINT,     -- NULL if not yet played
+    played_at TIMESTAMPTZ, -- Date and time the match was played
+    CONSTRAINT fk_teams_home_games FOREIGN KEY (home_team_id) REFERENCES teams(id),
+    CONSTRAINT fk_teams_away_games FOREIGN KEY (away_team_id) REFERENCES teams(id),
+    CONSTRAINT chk_matches_week CHECK (week >= 1 AND week <= 6) -- 6-week league
+);
+CREATE INDEX IF NOT EXISTS idx_matches_deleted_at ON matches(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_matches_played_at ON matches(played_at);
+CREATE INDEX IF NOT EXISTS idx_matches_week ON matches(week);
+
+-- Team statistics table
+-- Stores the results and the parameters required for the Poisson model
+CREATE TABLE IF NOT EXISTS team_stats (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    team_id BIGINT NOT NULL,
+    played BIGINT,
+    won BIGINT,
+    drawn BIGINT,
+    lost BIGINT,
+    goals_for BIGINT,
+    goals_away BIGINT,                -- Goals conceded
+    points BIGINT,
+    avg_scored DOUBLE PRECISION,      -- Average goals scored per match
+    avg_conceded DOUBLE PRECISION,    -- Average goals conceded per match
+    attack_strength DOUBLE PRECISION, -- Attack strength (λ_scored / λ_league)
+    defense_strength DOUBLE PRECISION, -- Defense strength (λ_conceded / λ_league)
+    CONSTRAINT fk_teams_stats FOREIGN KEY (team_id) REFERENCES teams(id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_team_stats_team_id ON team_stats(team_id);
+CREATE INDEX IF NOT EXISTS idx_team_stats_deleted_at ON team_stats(deleted_at);
+
+-- Championship predictions table
+-- Stores the results of Monte Carlo simulation
+CREATE TABLE IF NOT EXISTS predictions (
+    id BIGSERIAL PRIMARY KEY,
+    week BIGINT,
+    team_id BIGINT,
+    probability DOUBLE PRECISION, -- Percentage value (0.00–100.00)
+    created_at TIMESTAMPTZ,
+    CONSTRAINT fk_predictions_team FOREIGN KEY (team_id) REFERENCES teams(id)
+);
+CREATE INDEX IF NOT EXISTS idx_predictions_week ON predictions(week);
@@ -0,0 +1,15 @@
+-- Match events table
+-- Stores the timeline of each simulated match
+CREATE TABLE IF NOT EXISTS match_events (
+    id BIGSERIAL PRIMARY KEY,
+    match_id BIGINT NOT NULL,
+    minute BIGINT NOT NULL,
+    type VARCHAR(32) NOT NULL, -- goal, yellow_card, red_card, substitution, injury, half_time, full_time
+    team_id BIGINT,            -- NULL for half_time and full_time
+    player_id BIGINT,
+    assist_player_id BIGINT,
+    home_score BIGINT,
+    away_score BIGINT,
+    created_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_match_events_match_id ON match_events(match_id);
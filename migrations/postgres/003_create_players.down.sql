@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS player_absences;
+DROP TABLE IF EXISTS players;
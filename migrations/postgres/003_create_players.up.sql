@@ -0,0 +1,33 @@
+-- Players table
+-- Stores the squad of each team
+CREATE TABLE IF NOT EXISTS players (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    team_id BIGINT NOT NULL,
+    name TEXT NOT NULL,
+    shirt_number BIGINT,
+    position VARCHAR(2) NOT NULL, -- GK, DF, MF or FW
+    rating BIGINT,
+    scoring_share DOUBLE PRECISION,
+    CONSTRAINT fk_teams_players FOREIGN KEY (team_id) REFERENCES teams(id)
+);
+CREATE INDEX IF NOT EXISTS idx_players_team_id ON players(team_id);
+CREATE INDEX IF NOT EXISTS idx_players_deleted_at ON players(deleted_at);
+
+-- Player absences table
+-- Stores injuries and suspensions that keep players out of upcoming matches
+CREATE TABLE IF NOT EXISTS player_absences (
+    id BIGSERIAL PRIMARY KEY,
+    player_id BIGINT NOT NULL,
+    team_id BIGINT NOT NULL,
+    match_id BIGINT NOT NULL,    -- Match in which the absence was incurred
+    week BIGINT NOT NULL,
+    reason VARCHAR(16) NOT NULL, -- injury or suspension
+    matches BIGINT NOT NULL,     -- Number of team matches the player misses
+    created_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_player_absences_player_id ON player_absences(player_id);
+CREATE INDEX IF NOT EXISTS idx_player_absences_team_id ON player_absences(team_id);
+CREATE INDEX IF NOT EXISTS idx_player_absences_match_id ON player_absences(match_id);
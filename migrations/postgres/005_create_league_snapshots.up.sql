@@ -0,0 +1,10 @@
+-- League snapshots table
+-- Stores saved copies of the league's progress as JSON
+CREATE TABLE IF NOT EXISTS league_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    label VARCHAR(255),
+    week BIGINT, -- Last completed week when the snapshot was taken
+    data TEXT,
+    created_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_league_snapshots_created_at ON league_snapshots(created_at);
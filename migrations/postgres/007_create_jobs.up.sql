@@ -0,0 +1,16 @@
+-- Jobs table
+-- Stores long-running operations executed by the background job queue
+CREATE TABLE IF NOT EXISTS jobs (
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR(64),
+    params TEXT,        -- JSON encoded parameters
+    status VARCHAR(16), -- queued, running, succeeded, failed or cancelled
+    progress DOUBLE PRECISION,
+    result TEXT,        -- JSON encoded output
+    error TEXT,
+    created_at TIMESTAMPTZ,
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs(status);
+CREATE INDEX IF NOT EXISTS idx_jobs_created_at ON jobs(created_at);
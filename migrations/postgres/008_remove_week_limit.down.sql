@@ -0,0 +1,3 @@
+-- Fails while the league has matches after week 6
+ALTER TABLE matches DROP CONSTRAINT IF EXISTS chk_matches_week;
+ALTER TABLE matches ADD CONSTRAINT chk_matches_week CHECK (week >= 1 AND week <= 6);
@@ -0,0 +1,3 @@
+-- Imported leagues have more teams and weeks than the generated 6-week league
+ALTER TABLE matches DROP CONSTRAINT IF EXISTS chk_matches_week;
+ALTER TABLE matches ADD CONSTRAINT chk_matches_week CHECK (week >= 1);
@@ -0,0 +1,15 @@
+-- Match events table
+-- Stores the timeline of each simulated match
+CREATE TABLE IF NOT EXISTS match_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    match_id INTEGER NOT NULL,
+    minute INTEGER NOT NULL,
+    type VARCHAR(32) NOT NULL, -- goal, yellow_card, red_card, substitution, injury, half_time, full_time
+    team_id INTEGER,            -- NULL for half_time and full_time
+    player_id INTEGER,
+    assist_player_id INTEGER,
+    home_score INTEGER,
+    away_score INTEGER,
+    created_at DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_match_events_match_id ON match_events(match_id);
@@ -0,0 +1,10 @@
+-- Idempotency keys table
+-- Stores the responses of simulation requests sent with an Idempotency-Key header
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key VARCHAR(255) PRIMARY KEY,
+    method VARCHAR(10),
+    path VARCHAR(512),
+    status_code INTEGER, -- 0 while the original request is still in progress
+    response TEXT,
+    created_at DATETIME
+);
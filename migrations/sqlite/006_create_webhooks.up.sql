@@ -0,0 +1,29 @@
+-- Webhooks table
+-- Stores the HTTP endpoints notified of league events
+CREATE TABLE IF NOT EXISTS webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    url VARCHAR(2048) NOT NULL,
+    secret VARCHAR(255),
+    events VARCHAR(512), -- Comma separated subscribed events; empty means all events
+    active NUMERIC DEFAULT true,
+    created_at DATETIME
+);
+
+-- Webhook deliveries table
+-- Stores queued and completed deliveries of events to webhooks
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_id INTEGER,
+    event VARCHAR(64),
+    payload TEXT,
+    status VARCHAR(16), -- pending, delivered or failed
+    attempts INTEGER,
+    next_attempt_at DATETIME,
+    last_error TEXT,
+    response_status INTEGER,
+    created_at DATETIME,
+    delivered_at DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries(webhook_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_status ON webhook_deliveries(status);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_next_attempt_at ON webhook_deliveries(next_attempt_at);
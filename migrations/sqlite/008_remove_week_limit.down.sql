@@ -0,0 +1,23 @@
+-- Fails while the league has matches after week 6
+CREATE TABLE matches_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    week INTEGER NOT NULL,
+    home_team_id INTEGER NOT NULL,
+    away_team_id INTEGER NOT NULL,
+    home_goals INTEGER,     -- NULL if not yet played
+    away_goals INTEGER,     -- NULL if not yet played
+    played_at DATETIME, -- Date and time the match was played
+    CONSTRAINT fk_teams_home_games FOREIGN KEY (home_team_id) REFERENCES teams(id),
+    CONSTRAINT fk_teams_away_games FOREIGN KEY (away_team_id) REFERENCES teams(id),
+    CONSTRAINT chk_matches_week CHECK (week >= 1 AND week <= 6)
+);
+INSERT INTO matches_new (id, created_at, updated_at, deleted_at, week, home_team_id, away_team_id, home_goals, away_goals, played_at)
+    SELECT id, created_at, updated_at, deleted_at, week, home_team_id, away_team_id, home_goals, away_goals, played_at FROM matches;
+DROP TABLE matches;
+ALTER TABLE matches_new RENAME TO matches;
+CREATE INDEX IF NOT EXISTS idx_matches_deleted_at ON matches(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_matches_played_at ON matches(played_at);
+CREATE INDEX IF NOT EXISTS idx_matches_week ON matches(week);
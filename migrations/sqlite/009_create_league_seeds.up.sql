@@ -0,0 +1,7 @@
+-- League seeds table
+-- Holds the single random seed that the league's matches are simulated with
+CREATE TABLE IF NOT EXISTS league_seeds (
+    id INTEGER PRIMARY KEY,
+    seed BIGINT NOT NULL,
+    matches BIGINT NOT NULL DEFAULT 0 -- Matches simulated with the seed so far
+);